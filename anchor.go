@@ -0,0 +1,97 @@
+package glmenu
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+type Unit int
+
+const (
+	Pixels  Unit = 0
+	Percent      = 1
+)
+
+// Length is a distance measured either in pixels or as a percentage of the window
+type Length struct {
+	Value float32
+	Unit  Unit
+}
+
+// Px returns a length measured in pixels
+func Px(value float32) Length {
+	return Length{Value: value, Unit: Pixels}
+}
+
+// Pct returns a length measured as a percentage (0-100) of the window
+func Pct(value float32) Length {
+	return Length{Value: value, Unit: Percent}
+}
+
+// Resolve converts the length into pixels given the total space available along its axis
+func (l Length) Resolve(total float32) float32 {
+	if l.Unit == Percent {
+		return l.Value / 100 * total
+	}
+	return l.Value
+}
+
+// pixels resolves the length on a framebuffer where each logical pixel covers scale framebuffer pixels
+func (l Length) pixels(total, scale float32) float32 {
+	if l.Unit == Percent {
+		return l.Resolve(total)
+	}
+	return l.Value * scale
+}
+
+func (l Length) IsZero() bool {
+	return l.Value == 0
+}
+
+// Offset moves a menu away from its anchor point.
+// Positive X values move the menu right and positive Y values move it up.
+type Offset struct {
+	X, Y Length
+}
+
+// Size describes menu dimensions relative to the window.
+// A zero length falls back to the matching MenuDefaults.Dimensions value.
+type Size struct {
+	Width, Height Length
+}
+
+// DefaultOffset keeps a menu ScreenPadding pixels away from the window edges it is anchored to
+func DefaultOffset(anchor ScreenPosition) (offset Offset) {
+	switch anchor {
+	case ScreenTopLeft, ScreenLeft, ScreenLowerLeft:
+		offset.X = Px(ScreenPadding)
+	case ScreenTopRight, ScreenRight, ScreenLowerRight:
+		offset.X = Px(-ScreenPadding)
+	}
+	switch anchor {
+	case ScreenTopLeft, ScreenTopCenter, ScreenTopRight:
+		offset.Y = Px(-ScreenPadding)
+	case ScreenLowerLeft, ScreenLowerCenter, ScreenLowerRight:
+		offset.Y = Px(ScreenPadding)
+	}
+	return
+}
+
+// anchorPosition finds the center of a menu, relative to the center of the window, when the menu
+// is placed flush against the anchor and then moved by offset.  Pixel offsets are multiplied by scale.
+func anchorPosition(anchor ScreenPosition, offset Offset, windowWidth, windowHeight, width, height, scale float32) (center mgl32.Vec2) {
+	switch anchor {
+	case ScreenTopLeft, ScreenLeft, ScreenLowerLeft:
+		center[0] = -(windowWidth/2 - width/2)
+	case ScreenTopRight, ScreenRight, ScreenLowerRight:
+		center[0] = +(windowWidth/2 - width/2)
+	}
+	switch anchor {
+	case ScreenTopLeft, ScreenTopCenter, ScreenTopRight:
+		center[1] = +(windowHeight/2 - height/2)
+	case ScreenLowerLeft, ScreenLowerCenter, ScreenLowerRight:
+		center[1] = -(windowHeight/2 - height/2)
+	}
+	center[0] += offset.X.pixels(windowWidth, scale)
+	center[1] += offset.Y.pixels(windowHeight, scale)
+	return
+}
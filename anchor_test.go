@@ -0,0 +1,44 @@
+package glmenu
+
+import (
+	"testing"
+)
+
+func TestLengthResolve(t *testing.T) {
+	if v := Px(40).Resolve(800); v != 40 {
+		t.Error("pixels", v)
+	}
+	if v := Pct(-5).Resolve(800); v != -40 {
+		t.Error("percent", v)
+	}
+}
+
+func TestAnchorPositionScaled(t *testing.T) {
+	// HiDPI framebuffer of 1600x1200 holding a 400x200 menu; pixel offsets double while percentages do not
+	center := anchorPosition(ScreenTopRight, Offset{X: Pct(-5), Y: Px(-40)}, 1600, 1200, 400, 200, 2)
+	if center.X() != 520 || center.Y() != 420 {
+		t.Error(center)
+	}
+}
+
+func TestAnchorPosition(t *testing.T) {
+	// 800x600 window with a 200x100 menu
+	tests := []struct {
+		anchor ScreenPosition
+		offset Offset
+		x, y   float32
+	}{
+		{ScreenCenter, Offset{}, 0, 0},
+		{ScreenTopLeft, Offset{}, -300, 250},
+		{ScreenLowerRight, Offset{}, 300, -250},
+		{ScreenTopRight, Offset{X: Pct(-5), Y: Px(-40)}, 260, 210},
+		{ScreenLeft, DefaultOffset(ScreenLeft), -290, 0},
+		{ScreenLowerCenter, DefaultOffset(ScreenLowerCenter), 0, -240},
+	}
+	for _, test := range tests {
+		center := anchorPosition(test.anchor, test.offset, 800, 600, 200, 100, 1)
+		if center.X() != test.x || center.Y() != test.y {
+			t.Error(test.anchor, center, test.x, test.y)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package glmenu
+
+import (
+	"unicode"
+)
+
+type Direction int
+
+const (
+	DirectionAuto Direction = 0 // taken from the first strongly directional character, else from the menu
+	DirectionLTR            = 1
+	DirectionRTL            = 2
+)
+
+// bidiClass is a reduced set of the unicode bidirectional character types
+type bidiClass int
+
+const (
+	bidiL       bidiClass = iota // left to right letters
+	bidiR                        // hebrew, arabic and other right to left letters
+	bidiNumber                   // digits
+	bidiNeutral                  // spaces, punctuation and everything else
+)
+
+func classify(r rune) bidiClass {
+	switch {
+	case unicode.In(r, unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko):
+		if unicode.IsDigit(r) {
+			return bidiNumber
+		}
+		return bidiR
+	case unicode.IsDigit(r):
+		return bidiNumber
+	case unicode.IsLetter(r):
+		return bidiL
+	}
+	return bidiNeutral
+}
+
+// isRTL resolves DirectionAuto using the first strongly directional character of runes
+func (direction Direction) isRTL(runes []rune, fallback bool) bool {
+	switch direction {
+	case DirectionLTR:
+		return false
+	case DirectionRTL:
+		return true
+	}
+	for _, r := range runes {
+		switch classify(r) {
+		case bidiL:
+			return false
+		case bidiR:
+			return true
+		}
+	}
+	return fallback
+}
+
+var mirrored = map[rune]rune{
+	'(': ')', ')': '(',
+	'[': ']', ']': '[',
+	'{': '}', '}': '{',
+	'<': '>', '>': '<',
+	'«': '»', '»': '«',
+}
+
+// bidiOrder is a simplified form of the unicode bidirectional algorithm for a single line without
+// explicit embeddings.  Right to left runs are reversed and their brackets mirrored, numbers keep
+// reading left to right and neutrals take the direction of the text around them.
+// It returns the runes in display order, the embedding level of each logical rune and, for each
+// logical index, the index at which that rune is displayed.  Letters are not shaped.
+func bidiOrder(runes []rune, rtl bool) (visual []rune, levels []int, toVisual []int) {
+	base := 0
+	if rtl {
+		base = 1
+	}
+	levels = make([]int, len(runes))
+	classes := make([]bidiClass, len(runes))
+	for i, r := range runes {
+		classes[i] = classify(r)
+	}
+
+	// strong and numeric types
+	lastStrong := bidiL
+	if rtl {
+		lastStrong = bidiR
+	}
+	for i, class := range classes {
+		switch class {
+		case bidiL:
+			levels[i] = base * 2
+			lastStrong = bidiL
+		case bidiR:
+			levels[i] = 1
+			lastStrong = bidiR
+		case bidiNumber:
+			// numbers following left to right text are part of it, otherwise they form a run of their own
+			if base == 0 && lastStrong == bidiL {
+				levels[i] = 0
+			} else {
+				levels[i] = 2
+			}
+		}
+	}
+
+	// neutrals between two runs of the same direction join them, others take the base direction
+	for i := 0; i < len(classes); {
+		if classes[i] != bidiNeutral {
+			i++
+			continue
+		}
+		j := i
+		for j < len(classes) && classes[j] == bidiNeutral {
+			j++
+		}
+		// numbers count as right to left unless they are part of left to right text
+		rtlLike := func(k int) bool {
+			return classes[k] == bidiR || (classes[k] == bidiNumber && levels[k] > 0)
+		}
+		before, after := rtl, rtl
+		if i > 0 {
+			before = rtlLike(i - 1)
+		}
+		if j < len(classes) {
+			after = rtlLike(j)
+		}
+		level := base
+		if before == after {
+			if before {
+				level = 1
+			} else {
+				level = base * 2
+			}
+		}
+		for k := i; k < j; k++ {
+			levels[k] = level
+		}
+		i = j
+	}
+
+	// reverse every run at or above each odd level, highest first
+	toLogical := make([]int, len(runes))
+	for i := range toLogical {
+		toLogical[i] = i
+	}
+	highest := 0
+	for _, level := range levels {
+		if level > highest {
+			highest = level
+		}
+	}
+	for level := highest; level >= 1; level-- {
+		for i := 0; i < len(toLogical); {
+			if levels[toLogical[i]] < level {
+				i++
+				continue
+			}
+			j := i
+			for j < len(toLogical) && levels[toLogical[j]] >= level {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				toLogical[a], toLogical[b] = toLogical[b], toLogical[a]
+			}
+			i = j
+		}
+	}
+
+	visual = make([]rune, len(runes))
+	toVisual = make([]int, len(runes))
+	for v, l := range toLogical {
+		r := runes[l]
+		if levels[l]%2 == 1 {
+			if m, ok := mirrored[r]; ok {
+				r = m
+			}
+		}
+		visual[v] = r
+		toVisual[l] = v
+	}
+	return
+}
+
+// isRTL reports whether the menu lays itself out right to left
+func (menu *Menu) isRTL() bool {
+	return menu.Direction == DirectionRTL
+}
+
+// display puts str into display order
+func (menu *Menu) display(direction Direction, str string) string {
+	runes := []rune(str)
+	fallback := menu != nil && menu.isRTL()
+	visual, _, _ := bidiOrder(runes, direction.isRTL(runes, fallback))
+	return string(visual)
+}
+
+// mirror swaps left and right alignment for right to left menus
+func (align Alignment) mirror() Alignment {
+	switch align {
+	case AlignLeft:
+		return AlignRight
+	case AlignRight:
+		return AlignLeft
+	}
+	return align
+}
@@ -0,0 +1,64 @@
+package glmenu
+
+import (
+	"testing"
+)
+
+func TestBidiOrder(t *testing.T) {
+	tests := []struct {
+		logical  string
+		rtl      bool
+		expected string
+	}{
+		{"Options", false, "Options"},
+		{"שלום", false, "םולש"},
+		{"abc שלום 123", false, "abc 123 םולש"},
+		{"(abc)", true, "(abc)"},
+		{"שלום (1)", true, "(1) םולש"},
+	}
+	for _, test := range tests {
+		visual, _, _ := bidiOrder([]rune(test.logical), test.rtl)
+		if string(visual) != test.expected {
+			t.Errorf("%q: expected %q, got %q", test.logical, test.expected, string(visual))
+		}
+	}
+	if DirectionAuto.isRTL([]rune("123 שלום"), false) != true || DirectionAuto.isRTL([]rune("123"), true) != true {
+		t.Error("auto direction should follow the first strong character and otherwise the fallback")
+	}
+	if Alignment(AlignLeft).mirror() != AlignRight || AlignCenter.mirror() != AlignCenter {
+		t.Error("unexpected mirrored alignment")
+	}
+}
+
+func TestTextBoxBidiCursor(t *testing.T) {
+	openGLContext()
+
+	textbox := newTestTextBox(nil, "שלום")
+	if textbox.Text.String != "םולש" || textbox.Value() != "שלום" {
+		t.Fatal("unexpected text", textbox.Text.String, textbox.Value())
+	}
+	if textbox.caretBoundary(0) != 4 || textbox.caretBoundary(4) != 0 {
+		t.Error("the cursor should start on the right of right to left text")
+	}
+	textbox.moveVisual(-1)
+	if textbox.CursorIndex != 1 {
+		t.Error("moving left should advance through right to left text", textbox.CursorIndex)
+	}
+	if textbox.logicalIndex(0) != 4 {
+		t.Error("the left edge should be the end of the text", textbox.logicalIndex(0))
+	}
+
+	textbox.SetPlaceholder("שם")
+	if textbox.Placeholder.String != "םש" {
+		t.Error("placeholders should be displayed in visual order", textbox.Placeholder.String)
+	}
+
+	textbox.SetString("abc")
+	if textbox.toVisual != nil || textbox.CursorIndex != 1 {
+		t.Error("left to right text needs no reordering")
+	}
+	textbox.moveVisual(1)
+	if textbox.CursorIndex != 2 {
+		t.Error(textbox.CursorIndex)
+	}
+}
@@ -0,0 +1,302 @@
+package glmenu
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+)
+
+// Binding connects widgets to a value owned by the application.  Widgets display the value returned by Get
+// and write the user's edits back through Set.  Bindings made with BindPointer compare the variable on every
+// Draw and notice changes by themselves.  Otherwise code that changes the value behind the binding's back calls
+// Changed and every bound widget refreshes on its menu's next Draw.  Only the change counter is atomic, so
+// Changed may be called from any goroutine, but the value itself is read and written on the goroutine that
+// draws the menus: code changing it elsewhere must synchronize with the getter and setter itself.
+type Binding struct {
+	get     func() interface{}
+	set     func(value interface{}) error
+	watch   func() bool // reports whether the value differs from when it was last watched
+	version uint64
+}
+
+// Bind creates a binding from a getter and a setter.  A nil setter makes the binding read only.
+func Bind(get func() interface{}, set func(value interface{}) error) *Binding {
+	return &Binding{get: get, set: set, version: 1}
+}
+
+// BindPointer binds the variable ptr points to, ie &game.Players.  Set converts values to the
+// variable's type, parsing strings typed into textboxes and rounding numbers stored in integers.
+// Assignments made directly to the variable are redrawn without calling Changed.
+func BindPointer(ptr interface{}) (*Binding, error) {
+	target := reflect.ValueOf(ptr)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return nil, errors.New(fmt.Sprintf("BindPointer needs a non-nil pointer, not %T", ptr))
+	}
+	target = target.Elem()
+	last := target.Interface()
+	binding := Bind(
+		func() interface{} {
+			return target.Interface()
+		},
+		func(value interface{}) error {
+			converted, err := convertTo(value, target.Type())
+			if err != nil {
+				return err
+			}
+			target.Set(converted)
+			last = target.Interface()
+			return nil
+		},
+	)
+	binding.watch = func() bool {
+		current := target.Interface()
+		if reflect.DeepEqual(current, last) {
+			return false
+		}
+		last = current
+		return true
+	}
+	return binding, nil
+}
+
+func (b *Binding) Get() interface{} {
+	return b.get()
+}
+
+// Set stores value and refreshes the bound widgets
+func (b *Binding) Set(value interface{}) error {
+	if b.set == nil {
+		return errors.New("The binding is read only")
+	}
+	if err := b.set(value); err != nil {
+		return err
+	}
+	b.Changed()
+	return nil
+}
+
+// Changed tells the bound widgets that the value was modified outside of Set
+func (b *Binding) Changed() {
+	atomic.AddUint64(&b.version, 1)
+}
+
+func (b *Binding) current() uint64 {
+	return atomic.LoadUint64(&b.version)
+}
+
+// poll counts a change made directly to a watched value
+func (b *Binding) poll() {
+	if b.watch != nil && b.watch() {
+		b.Changed()
+	}
+}
+
+// bound is the binding state kept by a label or textbox
+type bound struct {
+	binding *Binding
+	seen    uint64 // version last displayed
+	failed  bool   // the last edit could not be stored and the display is out of date
+	update  func(value interface{})
+}
+
+func (b *bound) stale() bool {
+	return b.binding != nil && (b.failed || b.binding.current() != b.seen)
+}
+
+// refresh displays the value when it has changed since it was last displayed
+func (b *bound) refresh() bool {
+	if b.binding != nil {
+		b.binding.poll()
+	}
+	if !b.stale() {
+		return false
+	}
+	b.seen = b.binding.current()
+	b.failed = false
+	b.update(b.binding.Get())
+	return true
+}
+
+// push stores an edit.  Values that cannot be stored are reverted on the next refresh.
+func (b *bound) push(value interface{}) {
+	if b.binding == nil {
+		return
+	}
+	if err := b.binding.Set(value); err != nil {
+		b.failed = true
+		MenuDebug(err.Error())
+		return
+	}
+	b.seen = b.binding.current()
+}
+
+// convertTo converts value to typ, parsing strings and formatting values stored in strings
+func convertTo(value interface{}, typ reflect.Type) (reflect.Value, error) {
+	v := reflect.ValueOf(value)
+	fail := func() (reflect.Value, error) {
+		return reflect.Value{}, errors.New(fmt.Sprintf("Cannot convert %v (%T) to %s", value, value, typ))
+	}
+	if !v.IsValid() {
+		return fail()
+	}
+	if v.Type().AssignableTo(typ) {
+		return v, nil
+	}
+	converted := reflect.New(typ).Elem()
+	switch {
+	case typ.Kind() == reflect.String:
+		converted.SetString(fmt.Sprint(value))
+	case v.Kind() == reflect.String:
+		switch {
+		case typ.Kind() == reflect.Bool:
+			b, err := strconv.ParseBool(v.String())
+			if err != nil {
+				return fail()
+			}
+			converted.SetBool(b)
+		case isNumberKind(typ.Kind()):
+			number, err := strconv.ParseFloat(v.String(), 64)
+			if err != nil {
+				return fail()
+			}
+			if !setNumberChecked(converted, number) {
+				return fail()
+			}
+		default:
+			return fail()
+		}
+	case isNumberKind(v.Kind()) && isNumberKind(typ.Kind()):
+		if !setNumberChecked(converted, numberOf(v)) {
+			return fail()
+		}
+	case v.Kind() == typ.Kind() && v.Type().ConvertibleTo(typ):
+		converted = v.Convert(typ)
+	default:
+		return fail()
+	}
+	return converted, nil
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64 && kind != reflect.Uintptr
+}
+
+// setNumberChecked is setNumber for numbers that fit the value's type
+func setNumberChecked(value reflect.Value, number float64) bool {
+	rounded := math.Floor(number + 0.5)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.OverflowInt(int64(rounded)) || rounded != float64(int64(rounded)) {
+			return false
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rounded < 0 || value.OverflowUint(uint64(rounded)) || rounded != float64(uint64(rounded)) {
+			return false
+		}
+	default:
+		if value.OverflowFloat(number) {
+			return false
+		}
+	}
+	setNumber(value, number)
+	return true
+}
+
+// bind replaces the label's binding, update being called with the value whenever it changes
+func (label *Label) bind(binding *Binding, update func(value interface{})) {
+	label.binding = bound{binding: binding, update: update}
+	if label.binding.refresh() {
+		label.Menu.relayout()
+	}
+}
+
+// Bind displays the binding's value using format, ie "Players: %d", which defaults to %v.
+// Labels created with a translation key pass the value to the translation instead.  A nil binding unbinds the label.
+func (label *Label) Bind(binding *Binding, format string) {
+	if format == "" {
+		format = "%v"
+	}
+	label.bind(binding, func(value interface{}) {
+		if label.TextKey != "" {
+			label.textArgs = []interface{}{value}
+			label.setText(label.Menu.translate(label.TextKey, value))
+		} else {
+			label.setText(fmt.Sprintf(format, value))
+		}
+	})
+}
+
+// Bind displays the binding's value and stores each edit as it is typed.  Edits that cannot be stored,
+// ie letters in a number, are reverted once editing ends.  A nil binding unbinds the textbox.
+func (textbox *TextBox) Bind(binding *Binding) {
+	textbox.binding = bound{binding: binding, update: func(value interface{}) {
+		textbox.SetString("%v", value)
+		textbox.placeCursor()
+	}}
+	textbox.binding.refresh()
+}
+
+func (checkbox *Checkbox) Bind(binding *Binding) {
+	checkbox.Label.bind(binding, func(value interface{}) {
+		if converted, err := convertTo(value, reflect.TypeOf(false)); err == nil {
+			checkbox.Set(converted.Bool())
+		}
+	})
+}
+
+func (slider *Slider) Bind(binding *Binding) {
+	slider.Label.bind(binding, func(value interface{}) {
+		if converted, err := convertTo(value, reflect.TypeOf(float64(0))); err == nil {
+			slider.Set(converted.Float())
+		}
+	})
+}
+
+// Bind selects the option equal to the binding's value when it is a string and otherwise uses it as an index
+func (selector *Selector) Bind(binding *Binding) {
+	selector.Label.bind(binding, func(value interface{}) {
+		if str, ok := value.(string); ok {
+			for i, option := range selector.Options {
+				if option == str {
+					selector.Set(i)
+				}
+			}
+		} else if converted, err := convertTo(value, reflect.TypeOf(0)); err == nil {
+			selector.Set(int(converted.Int()))
+		}
+	})
+}
+
+// push stores the selected option in the same form as the bound value
+func (selector *Selector) push() {
+	b := &selector.Label.binding
+	if b.binding == nil {
+		return
+	}
+	if _, ok := b.binding.Get().(string); ok {
+		b.push(selector.Selected())
+	} else {
+		b.push(selector.Index)
+	}
+}
+
+// refreshBindings updates the elements whose bound values have changed
+func (menu *Menu) refreshBindings() {
+	changed := false
+	for _, label := range menu.Labels {
+		if label.binding.refresh() {
+			changed = true
+		}
+	}
+	for _, textbox := range menu.TextBoxes {
+		if !textbox.IsEdit {
+			textbox.binding.refresh()
+		}
+	}
+	if changed {
+		menu.relayout()
+	}
+}
@@ -0,0 +1,89 @@
+package glmenu
+
+import (
+	"testing"
+)
+
+func TestBindPointer(t *testing.T) {
+	lives := 3
+	binding, err := BindPointer(&lives)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		value    interface{}
+		expected int
+		ok       bool
+	}{
+		{5, 5, true},
+		{"42", 42, true},
+		{3.6, 4, true},
+		{"many", 4, false},
+		{true, 4, false},
+		{uint8(7), 7, true},
+	}
+	for _, test := range tests {
+		err := binding.Set(test.value)
+		if (err == nil) != test.ok || lives != test.expected {
+			t.Errorf("%v: expected %d, got %d (%v)", test.value, test.expected, lives, err)
+		}
+	}
+
+	name := ""
+	if binding, _ := BindPointer(&name); binding.Set(12) != nil || name != "12" {
+		t.Error("numbers should be stored in strings as text", name)
+	}
+	if err := Bind(func() interface{} { return 1 }, nil).Set(2); err == nil {
+		t.Error("read only bindings should refuse edits")
+	}
+	if _, err := BindPointer(lives); err == nil {
+		t.Error("binding a value rather than a pointer should fail")
+	}
+}
+
+func TestLabelBinding(t *testing.T) {
+	openGLContext()
+
+	players := 1
+	binding, _ := BindPointer(&players)
+	menu := &Menu{}
+	label := newTestLabel(menu, "", LabelConfig{})
+	menu.Labels = []*Label{label}
+
+	label.Bind(binding, "Players: %d")
+	if label.String() != "Players: 1" {
+		t.Fatal(label.String())
+	}
+	players = 2
+	menu.refreshBindings()
+	if label.String() != "Players: 2" {
+		t.Error("pointer bindings should notice assignments", label.String())
+	}
+
+	score := 10
+	label.Bind(Bind(func() interface{} { return score }, nil), "Score: %d")
+	score = 20
+	menu.refreshBindings()
+	if label.String() != "Score: 10" {
+		t.Error("getter bindings should wait for Changed", label.String())
+	}
+	label.binding.binding.Changed()
+	menu.refreshBindings()
+	if label.String() != "Score: 20" {
+		t.Error(label.String())
+	}
+
+	checkbox := &Checkbox{Label: newTestLabel(menu, "", LabelConfig{}), Text: "Sound"}
+	menu.Labels = append(menu.Labels, checkbox.Label)
+	sound := false
+	soundBinding, _ := BindPointer(&sound)
+	checkbox.Bind(soundBinding)
+	checkbox.Label.binding.push(true)
+	if !sound {
+		t.Error("pushed edits should be stored")
+	}
+	checkbox.Label.binding.push("loud")
+	if checkbox.Label.binding.refresh(); checkbox.Label.String() != "[x] Sound" {
+		t.Error("failed edits should be reverted", checkbox.Label.String())
+	}
+}
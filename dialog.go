@@ -0,0 +1,172 @@
+package glmenu
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	DialogLayer = 1000 // dialogs are drawn above all regular menus
+
+	promptWidth  = float32(250)
+	promptHeight = float32(40)
+)
+
+// dialogKeys are the translation keys of the dialog buttons
+var dialogKeys = []string{"Yes", "No", "OK", "Cancel"}
+
+// Confirm displays a modal dialog asking the user to choose between yes and no.
+// Either callback may be nil.  Escape is treated as no.  Yes and No are translation keys, see Localizer.
+func (mm *MenuManager) Confirm(title, message string, onYes, onNo func()) (*Menu, error) {
+	dialog, err := mm.newDialog(title, message)
+	if err != nil {
+		return nil, err
+	}
+	dialog.newButton("Yes", onYes)
+	dialog.newButton("No", onNo)
+	dialog.OnCancel = func() {
+		mm.closeDialog(dialog)
+		if onNo != nil {
+			onNo()
+		}
+	}
+	return mm.openDialog(dialog)
+}
+
+// Alert displays a modal message that is dismissed with a single OK button or escape
+func (mm *MenuManager) Alert(message string) (*Menu, error) {
+	dialog, err := mm.newDialog("", message)
+	if err != nil {
+		return nil, err
+	}
+	dialog.newButton("OK", nil)
+	return mm.openDialog(dialog)
+}
+
+// Prompt displays a modal dialog with a textbox holding value.
+// onSubmit receives the edited text when the user confirms with enter or the OK button.
+// OK and Cancel are translation keys like the buttons of Confirm and Alert.
+func (mm *MenuManager) Prompt(message, value string, onSubmit func(string)) (*Menu, error) {
+	dialog, err := mm.newDialog("", message)
+	if err != nil {
+		return nil, err
+	}
+	textbox := dialog.NewTextBox(value, promptWidth, promptHeight, 1)
+	submit := func() {
+		if onSubmit != nil {
+			onSubmit(textbox.Value())
+		}
+	}
+	dialog.newButton("OK", submit)
+	dialog.newButton("Cancel", nil)
+	dialog.OnEnterRelease = func() {
+		mm.closeDialog(dialog)
+		submit()
+	}
+	if _, err = mm.openDialog(dialog); err != nil {
+		return nil, err
+	}
+
+	// start editing immediately with the cursor after any existing text
+	textbox.IsEdit = true
+	textbox.MoveCursor(len([]rune(textbox.Value())))
+	return dialog, nil
+}
+
+// newDialog creates a temporary menu using DialogDefaults.  It is not part of the navigation history and,
+// since it isn't added to Menus, it never clashes with a menu's name or shows up in Validate and Graph.
+func (mm *MenuManager) newDialog(title, message string) (*Menu, error) {
+	if mm.window == nil {
+		return nil, errors.New("A menu must be created before a dialog can be displayed")
+	}
+	defaults := mm.DialogDefaults
+	if defaults == (MenuDefaults{}) {
+		defaults = mm.fallbackDefaults()
+	}
+	mm.dialogCount++
+	dialog, err := NewMenu(mm.window, fmt.Sprintf("dialog-%d", mm.dialogCount), mm.Font, defaults, ScreenCenter)
+	if err != nil {
+		return nil, err
+	}
+	mm.addDialog(dialog)
+	if title != "" {
+		dialog.NewHeader(title, Padding{Y: 5})
+	}
+	if message != "" {
+		dialog.NewLabel(message, LabelConfig{Action: NOOP, Padding: Padding{Y: 5}})
+	}
+	return dialog, nil
+}
+
+// addDialog keeps track of the dialog until it is closed.  Escape closes it unless OnCancel is replaced.
+func (mm *MenuManager) addDialog(dialog *Menu) {
+	dialog.MenuManager = mm
+	mm.dialogs = append(mm.dialogs, dialog)
+	dialog.IsModal = true
+	dialog.Layer = DialogLayer
+	dialog.DimColor = mm.DimColor
+	dialog.OnCancel = func() {
+		mm.closeDialog(dialog)
+	}
+}
+
+// newButton adds a label that closes the dialog before running action.
+// The English text doubles as the translation key, see Localizer.
+func (dialog *Menu) newButton(key string, action func()) *Label {
+	label := dialog.NewLabelKey(key, LabelConfig{Action: CUSTOM})
+	label.onRelease = func(xPos, yPos float64, button MouseClick, inBox bool) {
+		if inBox {
+			dialog.MenuManager.closeDialog(dialog)
+			if action != nil {
+				action()
+			}
+		}
+	}
+	return label
+}
+
+// fallbackDefaults borrows the look of the StartMenu for menus created on the fly
+func (mm *MenuManager) fallbackDefaults() MenuDefaults {
+	if start, ok := mm.Menus[mm.StartMenu]; ok {
+		return start.Defaults
+	}
+	return MenuDefaults{}
+}
+
+func (mm *MenuManager) openDialog(dialog *Menu) (*Menu, error) {
+	dialog.Finalize(AlignCenter)
+	dialog.Show()
+	return dialog, nil
+}
+
+// closeDialog hides the dialog immediately but waits until the next Draw to release its gl resources
+// since closing usually happens while the dialog's own labels are still being processed
+func (mm *MenuManager) closeDialog(dialog *Menu) {
+	for i := range mm.dialogs {
+		if mm.dialogs[i] == dialog {
+			mm.dialogs = append(mm.dialogs[:i:i], mm.dialogs[i+1:]...)
+			dialog.Hide()
+			mm.released = append(mm.released, dialog)
+			return
+		}
+	}
+}
+
+func (mm *MenuManager) closeDialogs() {
+	for len(mm.dialogs) > 0 {
+		mm.closeDialog(mm.dialogs[0])
+	}
+}
+
+func (mm *MenuManager) releaseClosed() {
+	for _, menu := range mm.released {
+		menu.Release()
+	}
+	mm.released = nil
+}
+
+// dimmed darkens everything drawn beneath the menu by stretching its background over the whole window
+func (menu *Menu) dimmed() mgl32.Mat4 {
+	return mgl32.Scale3D(menu.FramebufferWidth/menu.Width, menu.FramebufferHeight/menu.Height, 1)
+}
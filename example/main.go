@@ -45,6 +45,15 @@ func mouseButtonCallback(
 	}
 }
 
+func scrollCallback(w *glfw.Window, xOffset float64, yOffset float64) {
+	menuManager.MouseScroll(xOffset, yOffset)
+}
+
+func framebufferSizeCallback(w *glfw.Window, width int, height int) {
+	gl.Viewport(0, 0, int32(width), int32(height))
+	menuManager.Resize(float32(width), float32(height))
+}
+
 var window *glfw.Window
 var menuManager *glmenu.MenuManager
 
@@ -58,7 +67,7 @@ func main() {
 	}
 	defer glfw.Terminate()
 
-	glfw.WindowHint(glfw.Resizable, glfw.False)
+	glfw.WindowHint(glfw.Resizable, glfw.True)
 	glfw.WindowHint(glfw.ContextVersionMajor, 3)
 	glfw.WindowHint(glfw.ContextVersionMinor, 3)
 	if useStrictCoreProfile {
@@ -84,6 +93,8 @@ func main() {
 	window.MakeContextCurrent()
 	window.SetKeyCallback(keyCallback)
 	window.SetMouseButtonCallback(mouseButtonCallback)
+	window.SetFramebufferSizeCallback(framebufferSizeCallback)
+	window.SetScrollCallback(scrollCallback)
 
 	if err := gl.Init(); err != nil {
 		panic(err)
@@ -130,6 +141,7 @@ func main() {
 	// load menus
 	MenuInit(window, font)
 	menuManager.Show("main")
+	menuManager.Notify("Press escape to close the menu")
 
 	gl.ClearColor(0, 0, 0, 0.0)
 	for !window.ShouldClose() {
@@ -12,10 +12,16 @@ import (
 func MenuInit(window *glfw.Window, font *v41.Font) {
 	menuManager = glmenu.NewMenuManager(font, glfw.KeyM, "main")
 
+	// hover and click colors shared by every menu
+	hover := mgl32.Vec3{0, 250.0 / 255.0, 154.0 / 255.0}
+	click := mgl32.Vec3{250.0 / 255.0, 0, 154.0 / 255.0}
+	menuManager.Theme = &glmenu.Theme{
+		Hover:   glmenu.Style{TextColor: &hover},
+		Pressed: glmenu.Style{TextColor: &click},
+	}
+
 	defaults := glmenu.MenuDefaults{
 		TextColor:       mgl32.Vec3{1, 1, 1},
-		TextClick:       mgl32.Vec3{250.0 / 255.0, 0, 154.0 / 255.0},
-		TextHover:       mgl32.Vec3{0, 250.0 / 255.0, 154.0 / 255.0},
 		BackgroundColor: mgl32.Vec4{0.5, 0.5, 0.5, 1.0},
 		BorderColor:     mgl32.Vec4{1, 1, 1, 1.0},
 		Border:          mgl32.Vec2{2, 2},
@@ -35,7 +41,7 @@ func MenuInit(window *glfw.Window, font *v41.Font) {
 	textbox.Text.MaxRuneCount = 16
 	mainMenu.NewLabel("Options", glmenu.LabelConfig{Action: glmenu.GOTO_MENU, Goto: "option"})
 	mainMenu.NewLabel("Dummy", glmenu.LabelConfig{Action: glmenu.NOOP})
-	mainMenu.NewLabel("Quit", glmenu.LabelConfig{Action: glmenu.EXIT_GAME})
+	mainMenu.NewLabel("Quit", glmenu.LabelConfig{Action: glmenu.EXIT_GAME, Confirm: "Quit the game?"})
 
 	// menu 2
 	defaults = glmenu.MenuDefaults{
@@ -43,14 +49,37 @@ func MenuInit(window *glfw.Window, font *v41.Font) {
 		Dimensions:      mgl32.Vec2{200, 200},
 		Padding:         mgl32.Vec2{10, 10},
 		HoverPadding:    mgl32.Vec2{10, 10},
+		HeaderColor:     mgl32.Vec3{0, 0, 0},
+		SeparatorColor:  mgl32.Vec4{0, 0, 0, 0.5},
 	}
 	optionMenu, err := menuManager.NewMenu(window, "option", defaults, glmenu.ScreenTopCenter)
 	if err != nil {
 		fmt.Println("error loading font")
 		os.Exit(1)
 	}
-	optionMenu.NewLabel("Back", glmenu.LabelConfig{Action: glmenu.GOTO_MENU, Goto: "main"})
+	optionMenu.NewHeader("Options", glmenu.Padding{Y: 5})
+	optionMenu.NewSeparator(1, glmenu.Padding{Y: 5})
+	optionMenu.NewLabel("Back", glmenu.LabelConfig{Action: glmenu.GO_BACK})
+
+	// non-modal hud drawn beneath the other menus that never pauses the game
+	defaults = glmenu.MenuDefaults{
+		TextColor:       mgl32.Vec3{1, 1, 1},
+		BackgroundColor: mgl32.Vec4{0, 0, 0, 0.5},
+		Padding:         mgl32.Vec2{5, 5},
+	}
+	hudMenu, err := menuManager.NewMenu(window, "hud", defaults, glmenu.ScreenTopRight)
+	if err != nil {
+		fmt.Println("error loading font")
+		os.Exit(1)
+	}
+	hudMenu.IsModal = false
+	hudMenu.Layer = -1
+	hudMenu.NewLabel("HUD", glmenu.LabelConfig{Action: glmenu.NOOP})
+	hudMenu.Show()
 
 	// complete setup
-	menuManager.Finalize(glmenu.AlignRight)
+	if err := menuManager.Finalize(glmenu.AlignRight); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 }
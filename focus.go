@@ -0,0 +1,192 @@
+package glmenu
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"sort"
+)
+
+// focusGap is the logical space between the focused element and its ring
+const focusGap = 3
+
+// Focus moves keyboard focus to f, which is highlighted with its focused style and a ring drawn around it.
+// Focused labels are followed by enter and focused textboxes are edited.  OnBlur and OnFocus are called
+// when the focus changes.  Elements that are noop or disabled can't be focused.
+func (menu *Menu) Focus(f Formatable) {
+	if f == menu.focused || menu.IndexOf(f) < 0 || f.IsNoop() {
+		return
+	}
+	previous := menu.focused
+	menu.setFocus(f)
+	if previous != nil && menu.OnBlur != nil {
+		menu.OnBlur(previous)
+	}
+	if menu.OnFocus != nil {
+		menu.OnFocus(f)
+	}
+}
+
+// Blur removes the keyboard focus, ending any textbox edit
+func (menu *Menu) Blur() {
+	previous := menu.focused
+	if previous == nil {
+		return
+	}
+	menu.setFocus(nil)
+	if menu.OnBlur != nil {
+		menu.OnBlur(previous)
+	}
+}
+
+// Focused returns the element with the keyboard focus or nil
+func (menu *Menu) Focused() Formatable {
+	return menu.focused
+}
+
+// FocusNext moves the focus to the next element in tab order, wrapping around at the end.
+// Elements with a positive TabIndex come first, lowest first, followed by those with a zero TabIndex.
+// Ties keep the order of the menu.  Elements with a negative TabIndex, noop and disabled elements are skipped.
+func (menu *Menu) FocusNext() {
+	menu.focusStep(+1)
+}
+
+// FocusPrevious moves the focus to the previous element in tab order
+func (menu *Menu) FocusPrevious() {
+	menu.focusStep(-1)
+}
+
+func (menu *Menu) focusStep(step int) {
+	order := menu.tabOrder()
+	if len(order) == 0 {
+		return
+	}
+	next := 0
+	if step < 0 {
+		next = len(order) - 1
+	}
+	for i, f := range order {
+		if f == menu.focused {
+			next = (i + step + len(order)) % len(order)
+		}
+	}
+	menu.Focus(order[next])
+}
+
+// tabOrder lists the elements that tab traversal visits
+func (menu *Menu) tabOrder() []Formatable {
+	positive, zero := make([]Formatable, 0), make([]Formatable, 0)
+	for _, f := range menu.Formatable {
+		if f.IsNoop() {
+			continue
+		}
+		switch index := tabIndex(f); {
+		case index > 0:
+			positive = append(positive, f)
+		case index == 0:
+			zero = append(zero, f)
+		}
+	}
+	sort.SliceStable(positive, func(i, j int) bool {
+		return tabIndex(positive[i]) < tabIndex(positive[j])
+	})
+	return append(positive, zero...)
+}
+
+func tabIndex(f Formatable) int {
+	switch element := f.(type) {
+	case *Label:
+		return element.TabIndex
+	case *TextBox:
+		return element.TabIndex
+	}
+	return 0
+}
+
+// setFocus changes the focus without calling OnFocus or OnBlur
+func (menu *Menu) setFocus(f Formatable) {
+	if menu.focused != nil {
+		menu.focused.NavigateAway()
+	}
+	menu.focused = f
+	if f == nil {
+		return
+	}
+	// a label left hovered by the mouse must not compete with the focus, ie when enter is pressed
+	menu.clearHover()
+	menu.NavigationIndex = menu.IndexOf(f)
+	menu.NavigationVia = NavigationKey
+	menu.scrollIntoView(f)
+	f.NavigateTo()
+}
+
+// focusBounds returns the box, in framebuffer pixels from the center of the window, that the ring surrounds
+func (menu *Menu) focusBounds() (X1, X2 Point, ok bool) {
+	switch f := menu.focused.(type) {
+	case *Label:
+		x1, x2 := f.Text.GetBoundingBox()
+		return Point{x1.X, x1.Y}, Point{x2.X, x2.Y}, true
+	case *TextBox:
+		X1, X2 = f.GetBoundingBox()
+		X1.X, X1.Y = X1.X-f.border, X1.Y-f.border
+		X2.X, X2.Y = X2.X+f.border, X2.Y+f.border
+		return X1, X2, true
+	}
+	return
+}
+
+// focusRing returns the center and size of the four edges of the ring: bottom, top, left and right
+func focusRing(X1, X2 Point, gap, width float32) (centers, sizes [4]mgl32.Vec2) {
+	outer := gap + width
+	midX, midY := (X1.X+X2.X)/2, (X1.Y+X2.Y)/2
+	spanX, spanY := X2.X-X1.X+2*outer, X2.Y-X1.Y+2*gap
+	centers[0], sizes[0] = mgl32.Vec2{midX, X1.Y - gap - width/2}, mgl32.Vec2{spanX, width}
+	centers[1], sizes[1] = mgl32.Vec2{midX, X2.Y + gap + width/2}, mgl32.Vec2{spanX, width}
+	centers[2], sizes[2] = mgl32.Vec2{X1.X - gap - width/2, midY}, mgl32.Vec2{width, spanY}
+	centers[3], sizes[3] = mgl32.Vec2{X2.X + gap + width/2, midY}, mgl32.Vec2{width, spanY}
+	return
+}
+
+// focusStyle is the focused style of the focused element
+func (menu *Menu) focusStyle() ResolvedStyle {
+	switch f := menu.focused.(type) {
+	case *Label:
+		return f.style(StyleFocused)
+	case *TextBox:
+		return f.style(StyleFocused)
+	}
+	return menu.style(StyleFocused)
+}
+
+// drawFocus draws the ring around the focused element using the menu's shader and a unit quad
+func (menu *Menu) drawFocus() {
+	X1, X2, ok := menu.focusBounds()
+	if !ok || menu.Defaults.FocusWidth < 0 {
+		return
+	}
+	width := menu.Defaults.FocusWidth
+	if width == 0 {
+		width = 2
+	}
+	if menu.focusVao == 0 {
+		menu.focusVao, menu.focusVbo, menu.focusEbo = newUnitQuad(menu.position)
+	}
+	color := menu.focusStyle().RingColor
+	centers, sizes := focusRing(X1, X2, focusGap*menu.ContentScale, width*menu.ContentScale)
+
+	gl.UseProgram(menu.program)
+	gl.Uniform4fv(menu.backgroundUniform, 1, &color[0])
+	gl.UniformMatrix4fv(menu.orthographicUniform, 1, false, &menu.Font.OrthographicMatrix[0])
+	gl.Enable(gl.BLEND)
+	gl.BlendEquation(gl.FUNC_ADD)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.BindVertexArray(menu.focusVao)
+	for i := range centers {
+		scale := mgl32.Scale3D(sizes[i].X(), sizes[i].Y(), 1)
+		position := mgl32.Vec2{centers[i].X() / (menu.FramebufferWidth / 2), centers[i].Y() / (menu.FramebufferHeight / 2)}
+		gl.UniformMatrix4fv(menu.scaleUniform, 1, false, &scale[0])
+		gl.Uniform2fv(menu.finalPositionUniform, 1, &position[0])
+		gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, nil)
+	}
+	gl.BindVertexArray(0)
+	gl.Disable(gl.BLEND)
+}
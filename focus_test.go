@@ -0,0 +1,153 @@
+package glmenu
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"testing"
+)
+
+func TestFocus(t *testing.T) {
+	openGLContext()
+
+	menu := &Menu{}
+	header := &Header{Menu: menu}
+	play := newTestLabel(menu, "Play", LabelConfig{Action: GOTO_MENU})
+	quit := newTestLabel(menu, "Quit", LabelConfig{Action: EXIT_GAME})
+	menu.Formatable = []Formatable{header, play, quit}
+
+	events := ""
+	menu.OnFocus = func(f Formatable) { events += "focus " }
+	menu.OnBlur = func(f Formatable) { events += "blur " }
+
+	menu.Focus(header)
+	if menu.Focused() != nil {
+		t.Error("noop elements can't be focused")
+	}
+	hovered, focused, blurred := 0, 0, 0
+	play.OnHover = func(xPos, yPos float64, button MouseClick, inBox bool) { hovered++ }
+	play.OnFocus = func() { focused++ }
+	play.OnBlur = func() { blurred++ }
+	menu.Focus(play)
+	if focused != 1 || hovered != 0 {
+		t.Error("focusing a label should call its OnFocus rather than OnHover", focused, hovered)
+	}
+	menu.Focus(quit)
+	if blurred != 1 {
+		t.Error("moving the focus should call OnBlur", blurred)
+	}
+	if menu.Focused() != quit || play.focused || !quit.focused || menu.NavigationIndex != 2 {
+		t.Error("the focus should move to quit")
+	}
+	if quit.IsHover || quit.IsClick {
+		t.Error("focus should not fake hover or click state")
+	}
+	quit.SetEnabled(false)
+	if menu.Focused() != nil || quit.focused {
+		t.Error("disabling the focused label should blur it")
+	}
+	if events != "focus blur focus blur " {
+		t.Error(events)
+	}
+}
+
+func TestArrowKeysAtTheEnds(t *testing.T) {
+	openGLContext()
+
+	menu := &Menu{IsVisible: true}
+	play := newTestLabel(menu, "Play", LabelConfig{Action: CUSTOM})
+	quit := newTestLabel(menu, "Quit", LabelConfig{Action: CUSTOM})
+	menu.Labels = []*Label{play, quit}
+	menu.Formatable = []Formatable{play, quit}
+	menu.OnEnterRelease = menu.followFocus
+	followed, clicked := 0, 0
+	quit.onRelease = func(xPos, yPos float64, button MouseClick, inBox bool) { followed++ }
+	quit.OnClick = func(xPos, yPos float64, button MouseClick, inBox bool) { clicked++ }
+
+	menu.KeyRelease(glfw.KeyDown, false)
+	menu.KeyRelease(glfw.KeyDown, false)
+	menu.KeyRelease(glfw.KeyDown, false)
+	if menu.Focused() != quit || !quit.focused || play.focused {
+		t.Error("down on the last item should keep it focused and highlighted", menu.Focused())
+	}
+	menu.KeyRelease(glfw.KeyEnter, false)
+	if followed != 1 || clicked != 0 || quit.IsClick {
+		t.Error("enter should run the focused label's action without faking a click", followed, clicked)
+	}
+	menu.KeyRelease(glfw.KeyUp, false)
+	menu.KeyRelease(glfw.KeyUp, false)
+	if menu.Focused() != play || !play.focused || quit.focused {
+		t.Error("up on the first item should keep it focused and highlighted", menu.Focused())
+	}
+}
+
+func TestFocusRing(t *testing.T) {
+	centers, sizes := focusRing(Point{-10, -5}, Point{10, 5}, 2, 1)
+	expected := [4]mgl32.Vec2{{0, -7.5}, {0, 7.5}, {-12.5, 0}, {12.5, 0}}
+	if centers != expected {
+		t.Error(centers)
+	}
+	if sizes != [4]mgl32.Vec2{{26, 1}, {26, 1}, {1, 14}, {1, 14}} {
+		t.Error(sizes)
+	}
+}
+
+func TestTabOrder(t *testing.T) {
+	openGLContext()
+
+	menu := &Menu{}
+	name := newTestTextBox(menu, "")
+	email := newTestTextBox(menu, "")
+	email.TabIndex = 1
+	hidden := newTestTextBox(menu, "")
+	hidden.TabIndex = -1
+	submit := newTestLabel(menu, "Submit", LabelConfig{Action: CUSTOM})
+	back := newTestLabel(menu, "Back", LabelConfig{Action: GO_BACK})
+	back.SetEnabled(false)
+	menu.Formatable = []Formatable{name, email, hidden, &Separator{}, submit, back}
+
+	order := []Formatable{email, name, submit, email}
+	for i, expected := range order {
+		menu.FocusNext()
+		if menu.Focused() != expected {
+			t.Fatalf("tab %d focused %v", i, menu.Focused())
+		}
+	}
+	if !email.IsEdit {
+		t.Error("focused textboxes should be edited")
+	}
+	menu.FocusPrevious()
+	if menu.Focused() != submit || email.IsEdit {
+		t.Error("shift tab should go back and end the edit")
+	}
+}
+
+func TestEnterAdvances(t *testing.T) {
+	openGLContext()
+
+	menu := &Menu{IsVisible: true, EnterAdvances: true}
+	name := newTestTextBox(menu, "")
+	email := newTestTextBox(menu, "")
+	submit := newTestLabel(menu, "Submit", LabelConfig{Action: CUSTOM})
+	menu.Formatable = []Formatable{name, email, submit}
+	menu.sortElements()
+	entered := 0
+	menu.OnEnterRelease = func() { entered++ }
+
+	submit.IsHover = true
+	menu.Focus(name)
+	if submit.IsHover {
+		t.Error("moving the focus should clear the mouse hover")
+	}
+	menu.KeyRelease(glfw.KeyEnter, false)
+	if menu.Focused() != email || !email.IsEdit || name.IsEdit || entered != 0 {
+		t.Error("enter should advance from the edited textbox without calling OnEnterRelease", menu.Focused(), entered)
+	}
+	menu.KeyRelease(glfw.KeyEnter, false)
+	if menu.Focused() != submit || entered != 0 {
+		t.Error("enter should advance to the label", menu.Focused(), entered)
+	}
+	menu.KeyRelease(glfw.KeyEnter, false)
+	if menu.Focused() != submit || entered != 1 {
+		t.Error("enter on a label should call OnEnterRelease", entered)
+	}
+}
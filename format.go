@@ -7,8 +7,10 @@ import (
 type FormatableType int
 
 const (
-	FormatableLabel   = 0
-	FormatableTextbox = 1
+	FormatableLabel     = 0
+	FormatableTextbox   = 1
+	FormatableHeader    = 2
+	FormatableSeparator = 3
 )
 
 type Padding struct {
@@ -33,3 +35,16 @@ type Formatable interface {
 
 	Type() FormatableType
 }
+
+// releaser is implemented by elements that hold gl resources.  It is kept out of Formatable so that
+// existing implementations of that interface don't need to change.
+type releaser interface {
+	Release()
+}
+
+// release frees the gl resources held by f, if any
+func release(f Formatable) {
+	if r, ok := f.(releaser); ok {
+		r.Release()
+	}
+}
@@ -0,0 +1,93 @@
+package glmenu
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Graph describes how menus connect to one another through their labels
+type Graph struct {
+	Start string      `json:"start"`
+	Menus []GraphMenu `json:"menus"`
+}
+
+type GraphMenu struct {
+	Name  string      `json:"name"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphEdge is a label that leaves its menu.  To is only set for GOTO_MENU labels.
+type GraphEdge struct {
+	Label  string `json:"label"`
+	Action string `json:"action"`
+	To     string `json:"to,omitempty"`
+}
+
+// Graph collects the GOTO_MENU, GO_BACK, EXIT_MENU and EXIT_GAME labels of every menu, sorted by menu name
+func (mm *MenuManager) Graph() Graph {
+	graph := Graph{Start: mm.StartMenu, Menus: make([]GraphMenu, 0, len(mm.Menus))}
+	for _, menu := range mm.sortedMenus() {
+		node := GraphMenu{Name: menu.Name, Edges: make([]GraphEdge, 0)}
+		for _, label := range menu.Labels {
+			edge := GraphEdge{Label: label.String(), Action: label.Config.Action.String()}
+			switch label.Config.Action {
+			case GOTO_MENU:
+				edge.To = label.Config.Goto
+			case GO_BACK, EXIT_MENU, EXIT_GAME:
+			default:
+				continue
+			}
+			node.Edges = append(node.Edges, edge)
+		}
+		graph.Menus = append(graph.Menus, node)
+	}
+	return graph
+}
+
+// WriteJSON writes the menu graph as an adjacency list
+func (mm *MenuManager) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(mm.Graph())
+}
+
+// WriteDOT writes the menu graph in the Graphviz DOT language, ie for `dot -Tpng menus.dot`.
+// Menus are boxes, the StartMenu in bold.  Leaving the menus, quitting the game and going back
+// are drawn as ellipses shared by every menu.
+func (mm *MenuManager) WriteDOT(w io.Writer) error {
+	graph := mm.Graph()
+	lines := []string{"digraph menus {"}
+	for _, menu := range graph.Menus {
+		style := ""
+		if menu.Name == graph.Start {
+			style = ", style=bold"
+		}
+		lines = append(lines, fmt.Sprintf("\t%s [shape=box%s];", dotQuote(menu.Name), style))
+	}
+	pseudo := make(map[string]bool)
+	for _, menu := range graph.Menus {
+		for _, edge := range menu.Edges {
+			to := edge.To
+			if edge.Action != GOTO_MENU.String() {
+				to = "(" + edge.Action + ")"
+				if !pseudo[to] {
+					pseudo[to] = true
+					lines = append(lines, fmt.Sprintf("\t%s [shape=ellipse];", dotQuote(to)))
+				}
+			}
+			lines = append(lines, fmt.Sprintf("\t%s -> %s [label=%s];", dotQuote(menu.Name), dotQuote(to), dotQuote(edge.Label)))
+		}
+	}
+	lines = append(lines, "}")
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func dotQuote(str string) string {
+	str = strings.Replace(str, `\`, `\\`, -1)
+	str = strings.Replace(str, `"`, `\"`, -1)
+	str = strings.Replace(str, "\n", `\n`, -1)
+	return `"` + str + `"`
+}
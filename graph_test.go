@@ -0,0 +1,55 @@
+package glmenu
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestMenuManagerGraph(t *testing.T) {
+	openGLContext()
+
+	mm := newTestManager("main", "option")
+	mm.StartMenu = "main"
+	gotoLabel(mm.Menus["main"], "option")
+	for _, config := range []LabelConfig{{Action: EXIT_GAME}, {Action: NOOP}} {
+		mm.Menus["main"].Labels = append(mm.Menus["main"].Labels, newTestLabel(mm.Menus["main"], `Say "bye"`, config))
+	}
+	mm.Menus["option"].Labels = append(mm.Menus["option"].Labels, newTestLabel(mm.Menus["option"], "Back", LabelConfig{Action: GO_BACK}))
+
+	dot := &bytes.Buffer{}
+	if err := mm.WriteDOT(dot); err != nil {
+		t.Fatal(err)
+	}
+	expected := `digraph menus {
+	"main" [shape=box, style=bold];
+	"option" [shape=box];
+	"main" -> "option" [label="option"];
+	"(quit)" [shape=ellipse];
+	"main" -> "(quit)" [label="Say \"bye\""];
+	"(back)" [shape=ellipse];
+	"option" -> "(back)" [label="Back"];
+}
+`
+	if dot.String() != expected {
+		t.Errorf("unexpected dot output\n%s", dot.String())
+	}
+
+	out := &bytes.Buffer{}
+	if err := mm.WriteJSON(out); err != nil {
+		t.Fatal(err)
+	}
+	graph := Graph{}
+	if err := json.Unmarshal(out.Bytes(), &graph); err != nil {
+		t.Fatal(err)
+	}
+	if graph.Start != "main" || len(graph.Menus) != 2 || len(graph.Menus[0].Edges) != 2 {
+		t.Fatal("unexpected graph", out.String())
+	}
+	if edge := graph.Menus[0].Edges[0]; edge.Action != "goto" || edge.To != "option" {
+		t.Error("unexpected edge", edge)
+	}
+	if edge := graph.Menus[1].Edges[0]; edge.Action != "back" || edge.To != "" {
+		t.Error("unexpected edge", edge)
+	}
+}
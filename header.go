@@ -0,0 +1,82 @@
+package glmenu
+
+import (
+	"github.com/4ydx/gltext/v4.1"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Header is a non-interactive title used to group the elements that follow it
+type Header struct {
+	Menu     *Menu
+	Text     *v41.Text
+	TextKey  string // translation key, see SetKey
+	Padding  Padding
+	textArgs []interface{}
+}
+
+func (header *Header) GetPosition() mgl32.Vec2 {
+	return header.Text.Position
+}
+
+func (header *Header) SetPosition(v mgl32.Vec2) {
+	header.Text.SetPosition(v)
+}
+
+func (header *Header) GetPadding() Padding {
+	return header.Padding
+}
+
+func (header *Header) SetString(str string, argv ...interface{}) {
+	header.TextKey = ""
+	header.setText(sprintf(str, argv...))
+}
+
+// setText shows str, which has already been formatted or translated
+func (header *Header) setText(str string) {
+	header.Text.SetString(header.Menu.display(DirectionAuto, str))
+}
+
+// setScale sizes the header relative to the text of the labels around it
+func (header *Header) setScale(factor float32) {
+	scale := header.Menu.Defaults.HeaderScale
+	if scale == 0 {
+		scale = headerScale
+	}
+	header.Text.ScaleMin = scale * factor
+	header.Text.ScaleMax = scale * factor
+	header.Text.SetScale(scale * factor)
+}
+
+func (header *Header) Release() {
+	header.Text.Release()
+}
+
+func (header *Header) Draw() {
+	header.Text.Draw()
+}
+
+func (header *Header) Height() float32 {
+	return header.Text.Height()
+}
+
+func (header *Header) Width() float32 {
+	return header.Text.Width()
+}
+
+func (header *Header) Follow() bool {
+	return false
+}
+
+func (header *Header) NavigateTo() {}
+
+func (header *Header) NavigateAway() bool {
+	return false
+}
+
+func (header *Header) IsNoop() bool {
+	return true
+}
+
+func (header *Header) Type() FormatableType {
+	return FormatableHeader
+}
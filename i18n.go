@@ -0,0 +1,388 @@
+package glmenu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// Catalog holds the translations of one language.
+// Messages are either a plain string or, when they depend on a count, a set of plural forms keyed by
+// the CLDR categories zero, one, two, few, many and other.  Other is used when a form is missing.
+type Catalog struct {
+	Language string
+	Plural   func(n int) string // picks the plural category of n.  Defaults to a rule chosen by Language.
+	Messages map[string]Message
+}
+
+type Message struct {
+	Text   string
+	Plural map[string]string
+}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		return json.Unmarshal(data, &m.Plural)
+	}
+	return json.Unmarshal(data, &m.Text)
+}
+
+// catalogFile is the layout of a catalog file, ie
+//
+//	{"language": "de", "messages": {
+//		"menu.options": "Optionen",
+//		"menu.lives": {"one": "%d Leben übrig", "other": "%d Leben übrig"}
+//	}}
+type catalogFile struct {
+	Language string             `json:"language"`
+	Messages map[string]Message `json:"messages"`
+}
+
+// LoadCatalog reads a JSON catalog file
+func LoadCatalog(path string) (*Catalog, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseCatalog(path, data)
+}
+
+func parseCatalog(name string, data []byte) (*Catalog, error) {
+	file := catalogFile{}
+	if err := decodeJSON(name, data, &file); err != nil {
+		return nil, err
+	}
+	if file.Language == "" {
+		return nil, &LoadError{File: name, Line: 1, Column: 1, Err: errors.New("a catalog needs a language")}
+	}
+	return &Catalog{Language: file.Language, Messages: file.Messages}, nil
+}
+
+// Localizer translates keys into the current language, falling back to a second language for
+// keys that have not been translated yet
+type Localizer struct {
+	Language  string
+	Fallback  string
+	OnMissing func(language, key string) // called once per missing key.  Defaults to MenuDebug.
+
+	catalogs map[string]*Catalog
+	reported map[string]bool
+}
+
+func NewLocalizer(fallback string) *Localizer {
+	return &Localizer{
+		Language: fallback,
+		Fallback: fallback,
+		catalogs: make(map[string]*Catalog),
+		reported: make(map[string]bool),
+	}
+}
+
+// Add makes a catalog available, replacing any catalog of the same language
+func (l *Localizer) Add(catalog *Catalog) {
+	l.catalogs[catalog.Language] = catalog
+}
+
+// Languages lists the languages that have a catalog
+func (l *Localizer) Languages() []string {
+	languages := make([]string, 0, len(l.catalogs))
+	for language := range l.catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Translate looks up key and formats it with argv.  Messages with plural forms are chosen by the first
+// integer in argv.  Missing keys are reported and the key itself is returned so that the gap is visible.
+func (l *Localizer) Translate(key string, argv ...interface{}) string {
+	format, ok := l.lookup(l.Language, key, argv)
+	if !ok {
+		l.missing(l.Language, key)
+		if format, ok = l.lookup(l.Fallback, key, argv); !ok {
+			if l.Fallback != l.Language {
+				l.missing(l.Fallback, key)
+			}
+			format = key
+		}
+	}
+	if len(argv) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, argv...)
+}
+
+func (l *Localizer) lookup(language, key string, argv []interface{}) (string, bool) {
+	catalog, ok := l.catalogs[language]
+	if !ok {
+		return "", false
+	}
+	message, ok := catalog.Messages[key]
+	if !ok {
+		return "", false
+	}
+	if message.Plural == nil {
+		return message.Text, true
+	}
+	category := "other"
+	if n, ok := pluralCount(argv); ok {
+		plural := catalog.Plural
+		if plural == nil {
+			plural = pluralRule(language)
+		}
+		category = plural(n)
+	}
+	if form, ok := message.Plural[category]; ok {
+		return form, true
+	}
+	form, ok := message.Plural["other"]
+	return form, ok
+}
+
+func (l *Localizer) missing(language, key string) {
+	id := language + "\x00" + key
+	if l.reported[id] {
+		return
+	}
+	l.reported[id] = true
+	if l.OnMissing != nil {
+		l.OnMissing(language, key)
+		return
+	}
+	MenuDebug(fmt.Sprintf("Missing %s translation for '%s'", language, key))
+}
+
+// Missing lists the keys, out of those given, that the language's catalog does not translate
+func (l *Localizer) Missing(language string, keys []string) []string {
+	missing := make([]string, 0)
+	catalog := l.catalogs[language]
+	for _, key := range keys {
+		if catalog == nil {
+			missing = append(missing, key)
+		} else if _, ok := catalog.Messages[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+func pluralCount(argv []interface{}) (int, bool) {
+	for _, arg := range argv {
+		switch n := arg.(type) {
+		case int:
+			return n, true
+		case int8:
+			return int(n), true
+		case int16:
+			return int(n), true
+		case int32:
+			return int(n), true
+		case int64:
+			return int(n), true
+		case uint:
+			return int(n), true
+		case uint8:
+			return int(n), true
+		case uint16:
+			return int(n), true
+		case uint32:
+			return int(n), true
+		case uint64:
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// pluralRule returns the integer plural rule of a language such as "ru" or "pt-BR".
+// Languages without a rule of their own use the english one.
+func pluralRule(language string) func(n int) string {
+	base := strings.ToLower(language)
+	if i := strings.IndexAny(base, "-_"); i >= 0 {
+		base = base[:i]
+	}
+	slavic := func(n int, one string) string {
+		switch {
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		case one != "" && n%10 == 1 && n%100 != 11:
+			return one
+		}
+		return "many"
+	}
+	switch base {
+	case "ja", "zh", "ko", "vi", "th", "id", "ms":
+		return func(n int) string { return "other" }
+	case "fr":
+		return func(n int) string {
+			if n == 0 || n == 1 {
+				return "one"
+			}
+			return "other"
+		}
+	case "ru", "uk", "be":
+		return func(n int) string { return slavic(n, "one") }
+	case "pl":
+		return func(n int) string {
+			if n == 1 {
+				return "one"
+			}
+			return slavic(n, "")
+		}
+	case "cs", "sk":
+		return func(n int) string {
+			switch {
+			case n == 1:
+				return "one"
+			case n >= 2 && n <= 4:
+				return "few"
+			}
+			return "other"
+		}
+	case "he":
+		return func(n int) string {
+			switch n {
+			case 1:
+				return "one"
+			case 2:
+				return "two"
+			}
+			return "other"
+		}
+	case "ar":
+		return func(n int) string {
+			switch {
+			case n == 0:
+				return "zero"
+			case n == 1:
+				return "one"
+			case n == 2:
+				return "two"
+			case n%100 >= 3 && n%100 <= 10:
+				return "few"
+			case n%100 >= 11:
+				return "many"
+			}
+			return "other"
+		}
+	}
+	return func(n int) string {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+}
+
+// translate uses the MenuManager's Localizer when there is one and otherwise formats key as is
+func (menu *Menu) translate(key string, argv ...interface{}) string {
+	if menu.MenuManager != nil && menu.MenuManager.Localizer != nil {
+		return menu.MenuManager.Localizer.Translate(key, argv...)
+	}
+	if len(argv) == 0 {
+		return key
+	}
+	return fmt.Sprintf(key, argv...)
+}
+
+// NewLabelKey creates a label whose text is the translation of key.  See Label.SetKey.
+func (menu *Menu) NewLabelKey(key string, config LabelConfig, argv ...interface{}) *Label {
+	label := menu.NewLabel("", config)
+	label.SetKey(key, argv...)
+	return label
+}
+
+// NewHeaderKey creates a header whose text is the translation of key
+func (menu *Menu) NewHeaderKey(key string, padding Padding, argv ...interface{}) *Header {
+	header := menu.NewHeader("", padding)
+	header.SetKey(key, argv...)
+	return header
+}
+
+// SetKey translates key, formatting it with argv, and keeps the label translated whenever the language changes
+func (label *Label) SetKey(key string, argv ...interface{}) {
+	label.TextKey = key
+	label.textArgs = argv
+	label.setText(label.Menu.translate(key, argv...))
+	label.Menu.relayout()
+}
+
+func (header *Header) SetKey(key string, argv ...interface{}) {
+	header.TextKey = key
+	header.textArgs = argv
+	header.setText(header.Menu.translate(key, argv...))
+	header.Menu.relayout()
+}
+
+// SetPlaceholderKey shows the translation of key while the textbox is empty
+func (textbox *TextBox) SetPlaceholderKey(key string, argv ...interface{}) {
+	textbox.PlaceholderKey = key
+	textbox.placeholderArgs = argv
+	textbox.setPlaceholder(textbox.Menu.translate(key, argv...))
+}
+
+// SetLanguage switches every menu and open dialog to the language's catalog and lays them out again
+func (mm *MenuManager) SetLanguage(language string) error {
+	if mm.Localizer == nil {
+		return errors.New("A Localizer must be set before changing the language")
+	}
+	if _, ok := mm.Localizer.catalogs[language]; !ok {
+		return errors.New(fmt.Sprintf("No catalog has been added for the language '%s'", language))
+	}
+	mm.Localizer.Language = language
+	for _, menu := range mm.sortedMenus() {
+		menu.retranslate()
+	}
+	for _, dialog := range mm.dialogs {
+		dialog.retranslate()
+	}
+	return nil
+}
+
+func (menu *Menu) retranslate() {
+	for _, label := range menu.Labels {
+		if label.TextKey != "" {
+			label.setText(menu.translate(label.TextKey, label.textArgs...))
+		}
+	}
+	for _, header := range menu.Headers {
+		if header.TextKey != "" {
+			header.setText(menu.translate(header.TextKey, header.textArgs...))
+		}
+	}
+	for _, textbox := range menu.TextBoxes {
+		if textbox.PlaceholderKey != "" {
+			textbox.setPlaceholder(menu.translate(textbox.PlaceholderKey, textbox.placeholderArgs...))
+		}
+	}
+	menu.relayout()
+}
+
+// TextKeys lists every translation key used by the menus and dialog buttons, ie to check catalogs with Localizer.Missing
+func (mm *MenuManager) TextKeys() []string {
+	found := make(map[string]bool)
+	for _, key := range dialogKeys {
+		found[key] = true
+	}
+	for _, menu := range mm.Menus {
+		for _, label := range menu.Labels {
+			found[label.TextKey] = true
+		}
+		for _, header := range menu.Headers {
+			found[header.TextKey] = true
+		}
+		for _, textbox := range menu.TextBoxes {
+			found[textbox.PlaceholderKey] = true
+		}
+	}
+	delete(found, "")
+	keys := make([]string, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
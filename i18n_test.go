@@ -0,0 +1,119 @@
+package glmenu
+
+import (
+	"testing"
+)
+
+func TestLocalizer(t *testing.T) {
+	en, err := parseCatalog("en.json", []byte(`{"language": "en", "messages": {
+		"options": "Options",
+		"lives": {"one": "%d life left", "other": "%d lives left"},
+		"quit": "Quit"
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ru, err := parseCatalog("ru.json", []byte(`{"language": "ru", "messages": {
+		"options": "Настройки",
+		"lives": {"one": "Осталась %d жизнь", "few": "Осталось %d жизни", "many": "Осталось %d жизней"}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var missing []string
+	l := NewLocalizer("en")
+	l.OnMissing = func(language, key string) { missing = append(missing, language+":"+key) }
+	l.Add(en)
+	l.Add(ru)
+
+	if s := l.Translate("lives", 1); s != "1 life left" {
+		t.Error(s)
+	}
+	if s := l.Translate("lives", 3); s != "3 lives left" {
+		t.Error(s)
+	}
+	l.Language = "ru"
+	tests := map[int]string{1: "Осталась 1 жизнь", 3: "Осталось 3 жизни", 5: "Осталось 5 жизней", 11: "Осталось 11 жизней", 21: "Осталась 21 жизнь"}
+	for n, expected := range tests {
+		if s := l.Translate("lives", n); s != expected {
+			t.Errorf("%d: expected %q, got %q", n, expected, s)
+		}
+	}
+	if s := l.Translate("quit"); s != "Quit" {
+		t.Error("missing translations should fall back", s)
+	}
+	if s := l.Translate("credits"); s != "credits" {
+		t.Error("unknown keys should be shown as is", s)
+	}
+	l.Translate("quit")
+	if len(missing) != 3 || missing[0] != "ru:quit" || missing[1] != "ru:credits" || missing[2] != "en:credits" {
+		t.Error("each missing key should be reported once", missing)
+	}
+	if keys := l.Missing("ru", []string{"options", "quit"}); len(keys) != 1 || keys[0] != "quit" {
+		t.Error("unexpected missing keys", keys)
+	}
+
+	if _, err := parseCatalog("de.json", []byte(`{"messages": {}}`)); err == nil || err.Error() != "de.json:1:1: a catalog needs a language" {
+		t.Error("unexpected error", err)
+	}
+}
+
+func TestSetLanguage(t *testing.T) {
+	openGLContext()
+
+	en, _ := parseCatalog("en.json", []byte(`{"language": "en", "messages": {"name": "Your name", "play": "Play"}}`))
+	de, _ := parseCatalog("de.json", []byte(`{"language": "de", "messages": {"name": "Dein Name", "play": "Spielen"}}`))
+	mm := newTestManager("main")
+	mm.Localizer = NewLocalizer("en")
+	mm.Localizer.Add(en)
+	mm.Localizer.Add(de)
+	menu := mm.Menus["main"]
+	label := newTestLabel(menu, "", LabelConfig{Action: CUSTOM})
+	textbox := newTestTextBox(menu, "")
+	menu.Labels = []*Label{label}
+	menu.TextBoxes = []*TextBox{textbox}
+	label.SetKey("play")
+	textbox.SetPlaceholderKey("name")
+	dialog := &Menu{}
+	mm.addDialog(dialog)
+	button := newTestLabel(dialog, "", LabelConfig{Action: CUSTOM})
+	dialog.Labels = []*Label{button}
+	button.SetKey("play")
+	if textbox.Placeholder.String != "Your name" {
+		t.Fatal(textbox.Placeholder.String)
+	}
+
+	if err := mm.SetLanguage("de"); err != nil {
+		t.Fatal(err)
+	}
+	if label.String() != "Spielen" || textbox.Placeholder.String != "Dein Name" {
+		t.Error("switching the language should translate labels and placeholders", label.String(), textbox.Placeholder.String)
+	}
+	if button.String() != "Spielen" {
+		t.Error("open dialogs should be translated too", button.String())
+	}
+	if keys := mm.TextKeys(); len(keys) != 6 || keys[4] != "name" || keys[5] != "play" {
+		t.Error("unexpected keys", keys)
+	}
+}
+
+func TestPluralRule(t *testing.T) {
+	tests := []struct {
+		language string
+		n        int
+		expected string
+	}{
+		{"en-US", 0, "other"},
+		{"fr", 0, "one"},
+		{"pl", 22, "few"},
+		{"pl", 21, "many"},
+		{"ar", 2, "two"},
+		{"ar", 105, "few"},
+		{"ja", 1, "other"},
+	}
+	for _, test := range tests {
+		if category := pluralRule(test.language)(test.n); category != test.expected {
+			t.Errorf("%s %d: expected %s, got %s", test.language, test.n, test.expected, category)
+		}
+	}
+}
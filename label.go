@@ -1,8 +1,10 @@
 package glmenu
 
 import (
+	"fmt"
 	"github.com/4ydx/gltext/v4.1"
 	"github.com/go-gl/mathgl/mgl32"
+	"time"
 )
 
 type LabelAction int
@@ -12,12 +14,31 @@ const (
 	GOTO_MENU
 	EXIT_MENU
 	EXIT_GAME
+	GO_BACK
+	CUSTOM // interactive label whose behavior is defined entirely by OnRelease
 )
 
+var labelActionNames = map[LabelAction]string{
+	NOOP:      "noop",
+	GOTO_MENU: "goto",
+	EXIT_MENU: "exit",
+	EXIT_GAME: "quit",
+	GO_BACK:   "back",
+	CUSTOM:    "custom",
+}
+
+func (action LabelAction) String() string {
+	if name, ok := labelActionNames[action]; ok {
+		return name
+	}
+	return "unknown"
+}
+
 type LabelConfig struct {
 	Padding Padding
 	Action  LabelAction
 	Goto    string
+	Confirm string // when set the action only runs once the user agrees to this message
 }
 
 type LabelInteraction func(
@@ -27,11 +48,28 @@ type LabelInteraction func(
 )
 
 type Label struct {
-	Config  LabelConfig
-	Menu    *Menu
-	Text    *v41.Text
-	IsHover bool
-	IsClick bool
+	Config    LabelConfig
+	Menu      *Menu
+	Text      *v41.Text
+	Theme     *Theme    // overrides the menu's theme
+	TextKey   string    // translation key, see SetKey
+	Direction Direction // see SetDirection
+	IsHover   bool
+	IsClick   bool
+	disabled  bool // see SetEnabled
+	focused   bool // see Menu.Focus
+	TabIndex  int  // see Menu.FocusNext
+
+	textArgs []interface{}
+	text     string         // in logical order
+	adjust   func(step int) // widgets react to the left (-1) and right (+1) keys while selected
+	binding  bound
+	widget   persistent // the checkbox, slider or selector drawn by the label
+
+	// Provider produces the text while the menu is drawn, at most once per ProviderInterval, see SetProvider
+	Provider         func() string
+	ProviderInterval time.Duration
+	provided         time.Time
 
 	// public methods are expected to be defined by the user and run before the private method are called
 	// if a public method is undefined, it is skipped.  Currently I have only defined onRelease as private.
@@ -41,6 +79,8 @@ type Label struct {
 	OnRelease     LabelInteraction
 	OnHover       LabelInteraction
 	OnNotHover    func()
+	OnFocus       func() // called when keyboard navigation moves to the label
+	OnBlur        func() // called when keyboard navigation leaves the label
 }
 
 func (label *Label) Reset() {
@@ -55,22 +95,121 @@ func (label *Label) GetPadding() Padding {
 	return label.Config.Padding
 }
 
+// SetString replaces the text, which is no longer translated when the language changes
 func (label *Label) SetString(str string, argv ...interface{}) {
+	label.TextKey = ""
+	label.setText(sprintf(str, argv...))
+}
+
+// sprintf formats str only when there are arguments so that text containing % can be set as is
+func sprintf(str string, argv ...interface{}) string {
 	if len(argv) == 0 {
-		label.Text.SetString(str)
-	} else {
-		label.Text.SetString(str, argv...)
+		return str
+	}
+	return fmt.Sprintf(str, argv...)
+}
+
+// setText shows str, which has already been formatted or translated
+func (label *Label) setText(str string) {
+	label.text = str
+	label.Text.SetString(label.Menu.display(label.Direction, str))
+}
+
+// SetDirection changes how the label's text is ordered for display
+func (label *Label) SetDirection(direction Direction) {
+	label.Direction = direction
+	label.setText(label.text)
+	label.Menu.relayout()
+}
+
+// String returns the text in reading order rather than the order in which it is displayed
+func (label *Label) String() string {
+	return label.text
+}
+
+// SetProvider replaces the text with the result of provider, ie a frame counter, which is called while
+// the menu is drawn once every interval.  A zero interval calls it every frame.  Glyphs are only rebuilt
+// when the text changes and the menu is only laid out again when its width changes.  A nil provider stops the updates.
+func (label *Label) SetProvider(interval time.Duration, provider func() string) {
+	label.TextKey = ""
+	label.Provider = provider
+	label.ProviderInterval = interval
+	label.provided = time.Time{}
+}
+
+// SetTemplate is SetProvider for text formatted from values, ie
+//
+//	label.SetTemplate(time.Second/2, "Players: %d/%d", func() interface{} { return len(game.Players) }, func() interface{} { return game.MaxPlayers })
+func (label *Label) SetTemplate(interval time.Duration, format string, values ...func() interface{}) {
+	argv := make([]interface{}, len(values))
+	label.SetProvider(interval, func() string {
+		for i, value := range values {
+			argv[i] = value()
+		}
+		return sprintf(format, argv...)
+	})
+}
+
+// provide calls the provider when it is due and reports whether the label's width changed
+func (label *Label) provide(now time.Time) bool {
+	if label.Provider == nil || now.Sub(label.provided) < label.ProviderInterval {
+		return false
+	}
+	label.provided = now
+	str := label.Provider()
+	if str == label.text {
+		return false
+	}
+	width := label.Width()
+	label.setText(str)
+	return label.Width() != width
+}
+
+// refreshProviders updates the labels whose provider is due
+func (menu *Menu) refreshProviders(now time.Time) {
+	resized := false
+	for _, label := range menu.Labels {
+		if label.provide(now) {
+			resized = true
+		}
+	}
+	if resized {
+		menu.relayout()
+	}
+}
+
+// SetEnabled greys out a disabled label, which ignores the mouse and is skipped by keyboard navigation
+func (label *Label) SetEnabled(enabled bool) {
+	label.disabled = !enabled
+	if label.disabled {
+		if label.focused && label.Menu != nil && label.Menu.focused == label {
+			label.Menu.Blur()
+		}
+		label.IsHover = false
+		label.IsClick = false
+	}
+	label.Text.SetColor(label.style(label.restingState()).TextColor)
+	label.Text.SetScale(label.Text.ScaleMin)
+}
+
+// IsEnabled reports whether the label reacts to the mouse and keyboard, see SetEnabled
+func (label *Label) IsEnabled() bool {
+	return !label.disabled
+}
+
+// restingState is the style of the label while it isn't hovered or clicked
+func (label *Label) restingState() StyleState {
+	if label.disabled {
+		return StyleDisabled
 	}
+	return StyleNormal
 }
 
 func (label *Label) OrthoToScreenCoord() (X1 Point, X2 Point) {
 	if label.Menu != nil && label.Text != nil {
 		x1, x2 := label.Text.GetBoundingBox()
-		X1.X = x1.X + label.Menu.WindowWidth/2
-		X1.Y = x1.Y + label.Menu.WindowHeight/2
-
-		X2.X = x2.X + label.Menu.WindowWidth/2
-		X2.Y = x2.Y + label.Menu.WindowHeight/2
+		X1 = label.Menu.orthoToScreen(x1.X, x1.Y)
+		X2 = label.Menu.orthoToScreen(x2.X, x2.Y)
 	} else {
 		if label.Menu == nil {
 			MenuDebug("Uninitialized Menu Object")
@@ -89,7 +228,7 @@ func (label *Label) IsClicked(xPos, yPos float64, button MouseClick) {
 	// we have to transform them
 	X1, X2 := label.OrthoToScreenCoord()
 	inBox := float32(xPos) > X1.X && float32(xPos) < X2.X && float32(yPos) > X1.Y && float32(yPos) < X2.Y
-	if inBox {
+	if inBox && !label.disabled {
 		label.IsClick = true
 		if label.OnClick != nil {
 			label.OnClick(xPos, yPos, button, inBox)
@@ -112,32 +251,49 @@ func (label *Label) IsReleased(xPos, yPos float64, button MouseClick) {
 	X1, X2 := label.OrthoToScreenCoord()
 	inBox := float32(xPos) > X1.X && float32(xPos) < X2.X && float32(yPos) > X1.Y && float32(yPos) < X2.Y
 	if label.IsClick {
-		if label.IsHover {
-			label.Text.SetColor(label.Menu.Defaults.TextHover)
+		if label.focused {
+			label.Text.SetColor(label.style(StyleFocused).TextColor)
+		} else if label.IsHover {
+			label.Text.SetColor(label.style(StyleHover).TextColor)
 		} else {
-			label.Text.SetColor(label.Menu.Defaults.TextColor)
+			label.Text.SetColor(label.style(StyleNormal).TextColor)
 		}
 		if label.OnRelease != nil {
 			label.OnRelease(xPos, yPos, button, inBox)
 		}
 		if !label.StopOnRelease {
-			label.onRelease(xPos, yPos, button, inBox)
+			label.release(xPos, yPos, button, inBox)
 		}
 		label.StopOnRelease = false
 	}
 	label.IsClick = false
 }
 
+// release runs the internal action, first asking for confirmation when the label is configured to do so
+func (label *Label) release(xPos, yPos float64, button MouseClick, inBox bool) {
+	if inBox && label.Config.Confirm != "" && label.Menu != nil && label.Menu.MenuManager != nil {
+		label.Menu.MenuManager.Confirm("", label.Config.Confirm, func() {
+			label.onRelease(xPos, yPos, button, inBox)
+		}, nil)
+		return
+	}
+	label.onRelease(xPos, yPos, button, inBox)
+}
+
 // IsHovered uses a bounding box
 func (label *Label) IsHovered(xPos, yPos float64) {
 	X1, X2 := label.OrthoToScreenCoord()
 	inBox := float32(xPos) > X1.X && float32(xPos) < X2.X && float32(yPos) > X1.Y && float32(yPos) < X2.Y
-	label.IsHover = inBox
-	if inBox {
+	label.IsHover = inBox && !label.disabled
+	if label.IsHover {
 		label.OnHover(xPos, yPos, MouseUnclicked, inBox)
 	}
 }
 
+func (label *Label) Release() {
+	label.Text.Release()
+}
+
 func (label *Label) Draw() {
 	label.Text.Draw()
 }
@@ -154,16 +310,32 @@ func (label *Label) Width() float32 {
 	return label.Text.Width()
 }
 
+// NavigateTo highlights the label with its focused style and calls OnFocus.  It is called by Menu.Focus.
 func (label *Label) NavigateTo() {
-	point := label.InsidePoint()
-	if label.OnHover != nil {
-		label.IsHovered(float64(point.X), float64(point.Y))
+	if label.disabled {
+		return
+	}
+	label.focused = true
+	label.Text.SetColor(label.style(StyleFocused).TextColor)
+	label.Text.SetScale(label.Text.ScaleMax)
+	if label.OnFocus != nil {
+		label.OnFocus()
 	}
 }
 
 // NavigateAway if we end up needing to navigate away from this item then let the caller know
 // because it might need that information.  return value of 'true'
 func (label *Label) NavigateAway() bool {
+	if label.focused {
+		// the scale shrinks back gradually in OnNotHover
+		label.focused = false
+		label.IsHover = false
+		label.Text.SetColor(label.style(label.restingState()).TextColor)
+		if label.OnBlur != nil {
+			label.OnBlur()
+		}
+		return true
+	}
 	if label.IsHover {
 		label.IsHover = false
 		return true
@@ -171,18 +343,27 @@ func (label *Label) NavigateAway() bool {
 	return false
 }
 
+// Follow runs the label's action when it is focused or hovered, ie when enter is pressed.
+// OnRelease receives the label's center and MouseLeft, as it would for a click there.
 func (label *Label) Follow() bool {
-	if label.IsHover {
-		point := label.InsidePoint()
-		label.IsClicked(float64(point.X), float64(point.Y), MouseLeft)
-		label.IsReleased(float64(point.X), float64(point.Y), MouseLeft)
-		return true
+	if label.disabled || !(label.IsHover || label.focused) {
+		return false
 	}
-	return false
+	point := label.InsidePoint()
+	xPos, yPos := float64(point.X), float64(point.Y)
+	if label.OnRelease != nil {
+		label.OnRelease(xPos, yPos, MouseLeft, true)
+	}
+	if !label.StopOnRelease {
+		label.release(xPos, yPos, MouseLeft, true)
+	}
+	label.StopOnRelease = false
+	return true
 }
 
+// IsNoop reports whether keyboard navigation skips the label
 func (label *Label) IsNoop() bool {
-	return label.Config.Action == NOOP
+	return label.Config.Action == NOOP || label.disabled
 }
 
 func (label *Label) Type() FormatableType {
@@ -0,0 +1,455 @@
+package glmenu
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// Registry binds the callback names used in a menu file to Go functions.
+// Label callbacks may be a LabelInteraction, a func with the same signature or a func().
+// onNotHover and onCancel must be a func() while onShow and onHide must be a func(Transition), onShow setting Menu.OnShowVia.
+type Registry map[string]interface{}
+
+// LoadError points at the location in a menu file that could not be loaded
+type LoadError struct {
+	File         string
+	Line, Column int // zero when the location is unknown
+	Err          error
+}
+
+func (e *LoadError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Err)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Err)
+}
+
+var screenPositionNames = map[string]ScreenPosition{
+	"":            ScreenCenter,
+	"center":      ScreenCenter,
+	"topLeft":     ScreenTopLeft,
+	"topCenter":   ScreenTopCenter,
+	"topRight":    ScreenTopRight,
+	"left":        ScreenLeft,
+	"right":       ScreenRight,
+	"lowerLeft":   ScreenLowerLeft,
+	"lowerCenter": ScreenLowerCenter,
+	"lowerRight":  ScreenLowerRight,
+}
+
+// menuFile is the layout of a menu file:
+//
+//	{"menus": [{
+//		"name": "main", "position": "center", "defaults": {"textColor": [1, 1, 1], "padding": [10, 10]},
+//		"items": [
+//			{"header": "Main"},
+//			{"separator": 1},
+//			{"label": "Options", "action": "goto", "goto": "option"},
+//			{"label": "Name", "action": "custom", "onRelease": "editName"},
+//			{"textbox": "player", "width": 200, "height": 40, "border": 1},
+//			{"label": "Quit", "action": "quit", "confirm": "Quit the game?"}
+//		]
+//	}]}
+//
+// Actions are the names returned by LabelAction.String and default to noop.
+type menuFile struct {
+	Menus []menuDef `json:"menus"`
+}
+
+type menuDef struct {
+	Name     string       `json:"name"`
+	Position string       `json:"position"`
+	Defaults MenuDefaults `json:"defaults"`
+	Modal    *bool        `json:"modal"` // defaults to true
+	Layer    int          `json:"layer"`
+	Advance  bool         `json:"enterAdvances"` // see Menu.EnterAdvances
+	OnShow   string       `json:"onShow"`
+	OnHide   string       `json:"onHide"`
+	OnCancel string       `json:"onCancel"`
+	Items    []itemDef    `json:"items"`
+
+	position ScreenPosition
+	onShow   func(Transition)
+	onHide   func(Transition)
+	onCancel func()
+}
+
+type itemDef struct {
+	Label     *string  `json:"label"`
+	Textbox   *string  `json:"textbox"`
+	Header    *string  `json:"header"`
+	Separator *float32 `json:"separator"` // thickness
+
+	Padding  Padding `json:"padding"`
+	Disabled bool    `json:"disabled"` // labels and textboxes
+	TabIndex int     `json:"tabIndex"`
+
+	// labels
+	Action     string `json:"action"`
+	Goto       string `json:"goto"`
+	Confirm    string `json:"confirm"`
+	OnClick    string `json:"onClick"`
+	OnRelease  string `json:"onRelease"`
+	OnHover    string `json:"onHover"`
+	OnNotHover string `json:"onNotHover"`
+
+	// textboxes
+	ID     string  `json:"id"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+	Border int32   `json:"border"`
+
+	action     LabelAction
+	onClick    LabelInteraction
+	onRelease  LabelInteraction
+	onHover    LabelInteraction
+	onNotHover func()
+}
+
+// LoadFile creates the menus described by the JSON file at path.  See Load.
+func (mm *MenuManager) LoadFile(window *glfw.Window, path string, registry Registry) ([]*Menu, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return mm.Load(window, path, file, registry)
+}
+
+// Load creates the menus described by JSON read from r, naming callbacks found in registry.
+// name identifies the source in errors.  The whole description is checked before any menu is
+// created so a *LoadError leaves the MenuManager untouched, as does a failure to create a menu's
+// GL objects.  Call Finalize once all menus exist.  Only JSON is read; YAML is not supported.
+func (mm *MenuManager) Load(window *glfw.Window, name string, r io.Reader, registry Registry) ([]*Menu, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	defs, err := mm.parseMenus(name, data, registry, nil)
+	if err != nil {
+		return nil, err
+	}
+	return mm.build(window, defs)
+}
+
+// build creates the menus once their definitions have been checked
+func (mm *MenuManager) build(window *glfw.Window, defs []menuDef) ([]*Menu, error) {
+	menus := make([]*Menu, 0, len(defs))
+	for _, def := range defs {
+		menu, err := mm.NewMenu(window, def.Name, def.Defaults, def.position)
+		if err != nil {
+			mm.discard(menus)
+			return nil, err
+		}
+		if def.Modal != nil {
+			menu.IsModal = *def.Modal
+		}
+		menu.Layer = def.Layer
+		menu.EnterAdvances = def.Advance
+		menu.OnShowVia = def.onShow
+		menu.OnHide = def.onHide
+		menu.OnCancel = def.onCancel
+		for _, item := range def.Items {
+			switch {
+			case item.Label != nil:
+				label := menu.NewLabel(*item.Label, LabelConfig{Padding: item.Padding, Action: item.action, Goto: item.Goto, Confirm: item.Confirm})
+				if item.onClick != nil {
+					label.OnClick = item.onClick
+				}
+				if item.onRelease != nil {
+					label.OnRelease = item.onRelease
+				}
+				if item.onHover != nil {
+					label.OnHover = item.onHover
+				}
+				if item.onNotHover != nil {
+					label.OnNotHover = item.onNotHover
+				}
+				if item.Disabled {
+					label.SetEnabled(false)
+				}
+				label.TabIndex = item.TabIndex
+			case item.Textbox != nil:
+				textbox := menu.NewTextBox(*item.Textbox, item.Width, item.Height, item.Border)
+				textbox.ID = item.ID
+				textbox.TabIndex = item.TabIndex
+				if item.Disabled {
+					textbox.SetEnabled(false)
+				}
+			case item.Header != nil:
+				menu.NewHeader(*item.Header, item.Padding)
+			case item.Separator != nil:
+				menu.NewSeparator(*item.Separator, item.Padding)
+			}
+		}
+		menus = append(menus, menu)
+	}
+	return menus, nil
+}
+
+// discard unregisters and releases the menus created by a build that failed part way through
+func (mm *MenuManager) discard(menus []*Menu) {
+	for _, menu := range menus {
+		if mm.Menus[menu.Name] == menu {
+			delete(mm.Menus, menu.Name)
+		}
+		menu.Release()
+	}
+}
+
+// parseMenus decodes and checks a menu file, resolving names into positions, actions and callbacks.
+// Menus named in replacing are about to be released so their names may be reused.
+func (mm *MenuManager) parseMenus(name string, data []byte, registry Registry, replacing map[string]bool) ([]menuDef, error) {
+	file := menuFile{}
+	if err := decodeJSON(name, data, &file); err != nil {
+		return nil, err
+	}
+	fail := func(err error, path ...interface{}) error {
+		loadErr := &LoadError{File: name, Err: err}
+		loadErr.Line, loadErr.Column = lineColumn(data, locate(data, path...))
+		return loadErr
+	}
+
+	names := make(map[string]bool)
+	for i := range file.Menus {
+		def := &file.Menus[i]
+		if def.Name == "" {
+			return nil, fail(errors.New("a menu needs a name"), "menus", i)
+		}
+		if _, ok := mm.Menus[def.Name]; (ok && !replacing[def.Name]) || names[def.Name] {
+			return nil, fail(errors.New(fmt.Sprintf("The named menu %s already exists.", def.Name)), "menus", i, "name")
+		}
+		names[def.Name] = true
+
+		position, ok := screenPositionNames[def.Position]
+		if !ok {
+			return nil, fail(errors.New(fmt.Sprintf("unknown position '%s'", def.Position)), "menus", i, "position")
+		}
+		def.position = position
+
+		var err error
+		if def.onShow, err = registry.transition(def.OnShow); err != nil {
+			return nil, fail(err, "menus", i, "onShow")
+		}
+		if def.onHide, err = registry.transition(def.OnHide); err != nil {
+			return nil, fail(err, "menus", i, "onHide")
+		}
+		if def.onCancel, err = registry.function(def.OnCancel); err != nil {
+			return nil, fail(err, "menus", i, "onCancel")
+		}
+
+		for j := range def.Items {
+			item := &def.Items[j]
+			if err := registry.resolve(item); err != nil {
+				if located, ok := err.(*keyError); ok {
+					return nil, fail(located.err, "menus", i, "items", j, located.key)
+				}
+				return nil, fail(err, "menus", i, "items", j)
+			}
+		}
+	}
+	return file.Menus, nil
+}
+
+// keyError ties a problem to a single key of an item
+type keyError struct {
+	key string
+	err error
+}
+
+func (e *keyError) Error() string {
+	return e.err.Error()
+}
+
+func (registry Registry) resolve(item *itemDef) error {
+	kinds := 0
+	for _, isSet := range []bool{item.Label != nil, item.Textbox != nil, item.Header != nil, item.Separator != nil} {
+		if isSet {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return errors.New("an item needs exactly one of label, textbox, header or separator")
+	}
+	if item.Label == nil {
+		return nil
+	}
+
+	item.action = NOOP
+	if item.Action != "" {
+		found := false
+		for action, name := range labelActionNames {
+			if name == item.Action {
+				item.action, found = action, true
+			}
+		}
+		if !found {
+			return &keyError{"action", errors.New(fmt.Sprintf("unknown action '%s'", item.Action))}
+		}
+	}
+	if item.action == GOTO_MENU && item.Goto == "" {
+		return &keyError{"action", errors.New("a goto label needs a goto menu")}
+	}
+
+	var err error
+	if item.onClick, err = registry.interaction(item.OnClick); err != nil {
+		return &keyError{"onClick", err}
+	}
+	if item.onRelease, err = registry.interaction(item.OnRelease); err != nil {
+		return &keyError{"onRelease", err}
+	}
+	if item.onHover, err = registry.interaction(item.OnHover); err != nil {
+		return &keyError{"onHover", err}
+	}
+	if item.onNotHover, err = registry.function(item.OnNotHover); err != nil {
+		return &keyError{"onNotHover", err}
+	}
+	return nil
+}
+
+func (registry Registry) lookup(name string) (interface{}, error) {
+	callback, ok := registry[name]
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("no callback named '%s' has been registered", name))
+	}
+	return callback, nil
+}
+
+func (registry Registry) interaction(name string) (LabelInteraction, error) {
+	if name == "" {
+		return nil, nil
+	}
+	callback, err := registry.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	switch callback := callback.(type) {
+	case LabelInteraction:
+		return callback, nil
+	case func(xPos, yPos float64, button MouseClick, isInBoundingBox bool):
+		return callback, nil
+	case func():
+		return func(xPos, yPos float64, button MouseClick, isInBoundingBox bool) {
+			if isInBoundingBox {
+				callback()
+			}
+		}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("the callback '%s' is a %T rather than a LabelInteraction", name, callback))
+}
+
+func (registry Registry) function(name string) (func(), error) {
+	if name == "" {
+		return nil, nil
+	}
+	callback, err := registry.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if callback, ok := callback.(func()); ok {
+		return callback, nil
+	}
+	return nil, errors.New(fmt.Sprintf("the callback '%s' is a %T rather than a func()", name, callback))
+}
+
+func (registry Registry) transition(name string) (func(Transition), error) {
+	if name == "" {
+		return nil, nil
+	}
+	callback, err := registry.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if callback, ok := callback.(func(Transition)); ok {
+		return callback, nil
+	}
+	return nil, errors.New(fmt.Sprintf("the callback '%s' is a %T rather than a func(Transition)", name, callback))
+}
+
+// locate finds the offset of the value reached by following path, a list of object keys and
+// array indexes, through the JSON in data.  It returns the closest enclosing value it reached.
+func locate(data []byte, path ...interface{}) int64 {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	offset := int64(0)
+	for _, step := range path {
+		if _, err := decoder.Token(); err != nil {
+			return offset
+		}
+		found := false
+		for index := 0; decoder.More() && !found; index++ {
+			switch step := step.(type) {
+			case string:
+				key, err := decoder.Token()
+				if err != nil {
+					return offset
+				}
+				found = key == step
+			case int:
+				found = index == step
+			}
+			if !found {
+				var skip json.RawMessage
+				if err := decoder.Decode(&skip); err != nil {
+					return offset
+				}
+			}
+		}
+		if !found {
+			return offset
+		}
+		offset = skipSeparators(data, decoder.InputOffset())
+	}
+	return offset
+}
+
+func skipSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// decodeJSON unmarshals data into v.  Syntax and type errors are returned as a *LoadError pointing at
+// the line and column of the mistake within the file called name.
+func decodeJSON(name string, data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+	loadErr := &LoadError{File: name, Err: err}
+	switch err := err.(type) {
+	case *json.SyntaxError:
+		loadErr.Line, loadErr.Column = lineColumn(data, err.Offset)
+	case *json.UnmarshalTypeError:
+		loadErr.Line, loadErr.Column = lineColumn(data, err.Offset)
+	}
+	return loadErr
+}
+
+// lineColumn converts a byte offset into a one based line and column
+func lineColumn(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line, column = 1, 1
+	for _, b := range data[:offset] {
+		if b == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package glmenu
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMenuManagerParseMenus(t *testing.T) {
+	released := false
+	registry := Registry{
+		"quit":  func() { released = true },
+		"shown": func(via Transition) {},
+	}
+	data := []byte(`{"menus": [
+	{"name": "main", "position": "topLeft", "onShow": "shown", "defaults": {"textColor": [1, 0.5, 0]}, "items": [
+		{"header": "Main"},
+		{"label": "Options", "action": "goto", "goto": "option"},
+		{"label": "Quit", "action": "quit", "onRelease": "quit"}
+	]}
+]}`)
+	mm := newTestManager()
+	defs, err := mm.parseMenus("menus.json", data, registry, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := defs[0]
+	if def.position != ScreenTopLeft || def.onShow == nil || def.Defaults.TextColor[1] != 0.5 || len(def.Items) != 3 {
+		t.Fatal("unexpected definition", def)
+	}
+	if def.Items[0].Label != nil || def.Items[1].action != GOTO_MENU || def.Items[2].action != EXIT_GAME {
+		t.Error("unexpected items", def.Items)
+	}
+	def.Items[2].onRelease(0, 0, MouseLeft, true)
+	if !released {
+		t.Error("the registered callback should be bound")
+	}
+
+	tests := []struct {
+		data, expected string
+	}{
+		{`{"menus": [{"name": "main",}]}`, "menus.json:1:29: invalid character '}' looking for beginning of object key string"},
+		{"{\"menus\": [\n\t{\"name\": 1}\n]}", "menus.json:2:12: json: cannot unmarshal number"},
+		{"{\"menus\": [\n\t{\"name\": \"main\", \"position\": \"top\"}\n]}", "menus.json:2:31: unknown position 'top'"},
+		{"{\"menus\": [\n\t{\"name\": \"a\"},\n\t{\"name\": \"a\"}\n]}", "menus.json:3:11: The named menu a already exists."},
+		{"{\"menus\": [{\"name\": \"main\", \"items\": [\n\t{\"label\": \"Go\"},\n\t{\"label\": \"Go\", \"action\": \"gto\"}\n]}]}", "menus.json:3:28: unknown action 'gto'"},
+		{"{\"menus\": [{\"name\": \"main\", \"items\": [\n\t{\"label\": \"Go\", \"onHover\": \"missing\"}\n]}]}", "menus.json:2:29: no callback named 'missing' has been registered"},
+		{"{\"menus\": [{\"name\": \"main\", \"items\": [\n\t{\"label\": \"Go\", \"onClick\": \"shown\"}\n]}]}", "menus.json:2:29: the callback 'shown' is a func(glmenu.Transition) rather than a LabelInteraction"},
+		{"{\"menus\": [{\"name\": \"main\", \"items\": [\n\t{}\n]}]}", "menus.json:2:2: an item needs exactly one of label, textbox, header or separator"},
+	}
+	for _, test := range tests {
+		_, err := mm.parseMenus("menus.json", []byte(test.data), registry, nil)
+		if err == nil || !strings.HasPrefix(err.Error(), test.expected) {
+			t.Errorf("expected %q, got %v", test.expected, err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package glmenu
 
 import (
+	"fmt"
 	"github.com/4ydx/gltext"
 	"github.com/4ydx/gltext/v4.1"
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -8,6 +9,7 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 	"image"
 	"math"
+	"time"
 )
 
 type Point struct {
@@ -71,16 +73,44 @@ const (
 	ScreenPadding = float32(10) // used by screen positioning calculations
 )
 
+// Transition describes how a menu came to be shown or hidden
+type Transition int
+
+const (
+	TransitionNone    Transition = 0 // Show, Hide or Toggle called directly
+	TransitionForward            = 1 // MenuManager.Push, ie following a GOTO_MENU label
+	TransitionBack               = 2 // MenuManager.Pop, ie a GO_BACK label or the escape key
+	TransitionReplace            = 3 // MenuManager.Replace
+)
+
+type Overflow int
+
+const (
+	OverflowClip   Overflow = 0 // content beyond MaxSize is hidden
+	OverflowScroll          = 1 // content beyond MaxSize can be scrolled into view
+	OverflowShrink          = 2 // text is scaled down until the content fits, anything remaining is clipped
+
+	labelScaleMax = float32(1.1)  // hovered text grows up to this multiple of its resting scale
+	headerScale   = float32(1.25) // used when MenuDefaults.HeaderScale is unset
+)
+
 type MenuDefaults struct {
 	TextColor       mgl32.Vec3
 	TextHover       mgl32.Vec3
 	TextClick       mgl32.Vec3
+	TextDisabled    mgl32.Vec3 // defaults to TextColor at half brightness when unset
 	BackgroundColor mgl32.Vec4
 	BorderColor     mgl32.Vec4
 	Border          mgl32.Vec2
 	Dimensions      mgl32.Vec2
+	Size            Size // window relative dimensions, overriding Dimensions when set
 	Padding         mgl32.Vec2
 	HoverPadding    mgl32.Vec2
+	HeaderColor     mgl32.Vec3
+	HeaderScale     float32
+	SeparatorColor  mgl32.Vec4
+	FocusColor      mgl32.Vec4 // ring drawn around the focused element.  Defaults to TextHover.
+	FocusWidth      float32    // logical width of the ring.  Defaults to 2, negative values hide the ring.
 
 	// increment during a scale operation
 	TextScaleRate float32
@@ -94,37 +124,68 @@ type Menu struct {
 
 	// trigger
 	OnShow         func()
+	OnShowVia      func(via Transition) // called after OnShow with the way the menu was reached
+	OnHide         func(via Transition)
 	OnComplete     func()
 	OnEnterRelease func()
+	OnCancel       func() // called when escape is pressed, replacing the default of navigating back
+	EnterAdvances  bool   // enter moves from an edited textbox to the next field, see FocusNext, instead of calling OnEnterRelease
 
 	// options
 	Defaults     MenuDefaults
+	Theme        *Theme    // overrides the MenuManager's theme.  Use SetTheme once the menu has elements.
+	Direction    Direction // DirectionRTL mirrors AlignLeft and AlignRight.  Elements detect their own direction by default.
 	IsVisible    bool
-	ShowOnKey    glfw.Key
+	IsModal      bool       // modal menus receive all input, blocking menus beneath them.  Defaults to true.
+	DimColor     mgl32.Vec4 // when not transparent it is drawn over everything beneath the menu
+	Layer        int        // menus on higher layers are drawn above lower layers.  Read when the menu is shown.
+	ShowOnKey    glfw.Key   // toggles the menu.  Defaults to glfw.KeyUnknown which disables the hotkey.
 	Height       float32
 	Width        float32
 	IsAutoCenter bool
+	MinSize      Size // zero lengths fall back to MenuDefaults.Dimensions and MenuDefaults.Size
+	MaxSize      Size // zero lengths leave that dimension unbounded
+	Overflow     Overflow
+	ScrollStep   float32 // logical pixels scrolled per mouse wheel step
 	lowerLeft    Point
+	align        Alignment
+	isFinalized  bool
+	textFactor   float32 // scale applied by shrinkText, zero when the text has its natural size
+	overflowing  bool
+	scrollOffset float32
+	settings     *SettingsMenu // set when the menu edits a struct, see ValueFile.Load
 
 	// interactive objects
 	Font       *v41.Font
 	Labels     []*Label
 	TextBoxes  []*TextBox
-	Formatable []Formatable // all labels and textboxes
+	Headers    []*Header
+	Separators []*Separator
+	Formatable []Formatable // all labels, textboxes, headers and separators
 
 	// Up/Down keypress -> NavigationVia set to "Key"
 	// When in "Key", mouse navigation only happens once the mouse has been moved enough from LastMousePosition
 	LastMousePosition mgl32.Vec2
 	NavigationVia     Navigation
 	NavigationIndex   int // once up/down arrows are pressed, determine which element needs to be entered/hovered over
+	OnFocus           func(f Formatable)
+	OnBlur            func(f Formatable)
+	focused           Formatable // see Focus
+	focusVao          uint32
+	focusVbo          uint32
+	focusEbo          uint32
 
 	// opengl oriented
-	ScreenPosition       ScreenPosition
+	ScreenPosition       ScreenPosition // anchor
+	ScreenOffset         Offset         // distance from the anchor
 	screenPositionOffset mgl32.Vec2
 	Window               *glfw.Window
-	WindowWidth          float32
+	WindowWidth          float32 // logical size matching glfw cursor positions, used for layout
 	WindowHeight         float32
-	program              uint32 // shader program
+	FramebufferWidth     float32 // pixel size used for rendering
+	FramebufferHeight    float32
+	ContentScale         float32 // framebuffer pixels per logical unit, greater than 1 on HiDPI displays
+	program              uint32  // shader program
 	backgroundUniform    int32
 	orthographicUniform  int32 // ortho matrix
 	finalPositionUniform int32 // offset to reposition based on ScreenPosition
@@ -143,15 +204,34 @@ type Menu struct {
 	eboIndexCount int
 }
 
+// Finalize lays out the menu for the current window size and uploads the background to the gpu
 func (menu *Menu) Finalize(align Alignment) {
+	width, height := menu.Window.GetSize()
+	menu.ResizeWindow(float32(width), float32(height))
+
+	menu.align = align
+	menu.layout()
+	menu.isFinalized = true
+}
+
+// layout positions every element along with the menu background.
+// It is safe to call repeatedly, ie after the window has been resized.
+func (menu *Menu) layout() {
 	glfloat_size := 4
 	glint_size := 4
 
+	for i := range menu.TextBoxes {
+		menu.TextBoxes[i].rescale()
+	}
+	align := menu.align
+	if menu.isRTL() {
+		align = align.mirror()
+	}
 	menu.format(align)
 	menu.lowerLeft = menu.findCenter()
 	menu.finalPosition = mgl32.Vec2{
-		menu.screenPositionOffset.X() / (menu.WindowWidth / 2),
-		menu.screenPositionOffset.Y() / (menu.WindowHeight / 2),
+		menu.screenPositionOffset.X() / (menu.FramebufferWidth / 2),
+		menu.screenPositionOffset.Y() / (menu.FramebufferHeight / 2),
 	}
 	menu.makeBufferData()
 
@@ -159,8 +239,8 @@ func (menu *Menu) Finalize(align Alignment) {
 
 	// create a 5 pixel border
 	menu.scaleMatrix = mgl32.Scale3D(
-		1+menu.Defaults.Border.X()/(menu.Width/2),
-		1+menu.Defaults.Border.Y()/(menu.Height/2),
+		1+menu.Defaults.Border.X()*menu.ContentScale/(menu.Width/2),
+		1+menu.Defaults.Border.Y()*menu.ContentScale/(menu.Height/2),
 		1,
 	)
 
@@ -181,7 +261,7 @@ func (menu *Menu) Finalize(align Alignment) {
 // the texture.  The image will be subdivided into evenly spaced rectangles based on the dimensions given.
 // This is very beta.
 func (menu *Menu) NewMenuTexture(imagePath string, dimensions mgl32.Vec2) (mt *MenuTexture, err error) {
-	width, height := menu.Window.GetSize()
+	width, height := menu.Window.GetFramebufferSize()
 	mt = &MenuTexture{
 		Menu:         menu,
 		WindowWidth:  float32(width),
@@ -243,14 +323,15 @@ func (menu *Menu) NewLabel(str string, config LabelConfig) *Label {
 	label := &Label{
 		Config: config,
 		Menu:   menu,
-		Text:   v41.NewText(menu.Font, 1.0, 1.1),
+		Text:   v41.NewText(menu.Font, 1.0, labelScaleMax),
 	}
 	menu.Labels = append(menu.Labels, label)
 	menu.Formatable = append(menu.Formatable, label)
 
 	label.SetString(str)
-	label.Text.SetScale(1)
-	label.Text.SetColor(menu.Defaults.TextColor)
+	label.rescale(1)
+	label.Text.SetColor(label.style(StyleNormal).TextColor)
+	menu.relayout()
 
 	if config.Action == NOOP {
 		label.onRelease = func(xPos, yPos float64, button MouseClick, inBox bool) {}
@@ -258,25 +339,53 @@ func (menu *Menu) NewLabel(str string, config LabelConfig) *Label {
 	}
 
 	label.OnClick = func(xPos, yPos float64, button MouseClick, inBox bool) {
-		label.Text.SetColor(menu.Defaults.TextClick)
+		label.Text.SetColor(label.style(StylePressed).TextColor)
 	}
 	label.OnHover = func(xPos, yPos float64, button MouseClick, inBox bool) {
 		if !label.IsClick {
-			label.Text.SetColor(menu.Defaults.TextHover)
+			label.Text.SetColor(label.style(StyleHover).TextColor)
 			label.Text.AddScale(menu.Defaults.TextScaleRate)
 		}
 	}
 	label.OnNotHover = func() {
 		if !label.IsClick {
-			label.Text.SetColor(menu.Defaults.TextColor)
+			label.Text.SetColor(label.style(StyleNormal).TextColor)
 			label.Text.AddScale(-menu.Defaults.TextScaleRate)
 		}
 	}
 	switch config.Action {
+	case GOTO_MENU:
+		// resolved when clicked so that the target may be created or replaced at any time
+		label.onRelease = func(xPos, yPos float64, button MouseClick, inBox bool) {
+			if !inBox || menu.MenuManager == nil {
+				return
+			}
+			to, ok := menu.MenuManager.Menus[label.Config.Goto]
+			if !ok {
+				MenuDebug(fmt.Sprintf("GOTO_MENU target '%s' doesn't exist", label.Config.Goto))
+				return
+			}
+			menu.MenuManager.makeCurrent(menu)
+			menu.MenuManager.push(to, TransitionForward)
+		}
 	case EXIT_MENU:
 		label.onRelease = func(xPos, yPos float64, button MouseClick, inBox bool) {
 			if inBox {
 				menu.Hide()
+				if menu.MenuManager != nil {
+					menu.MenuManager.history = nil
+				}
+			}
+		}
+	case GO_BACK:
+		label.onRelease = func(xPos, yPos float64, button MouseClick, inBox bool) {
+			if inBox {
+				if menu.MenuManager == nil {
+					menu.Hide()
+					return
+				}
+				menu.MenuManager.makeCurrent(menu)
+				menu.MenuManager.Pop()
 			}
 		}
 	case EXIT_GAME:
@@ -291,104 +400,193 @@ func (menu *Menu) NewLabel(str string, config LabelConfig) *Label {
 	return label
 }
 
+// format works in framebuffer pixels.  Element sizes reported by the font already are, while
+// the logical values found in MenuDefaults and LabelConfig are multiplied by the content scale.
 func (menu *Menu) format(align Alignment) {
-	var borders float32
-	height, width := float32(0), float32(0)
-	hTotal, wTotal := float32(0), float32(0)
-	length := len(menu.Formatable)
-	for _, l := range menu.Formatable {
-		if l.Height() > height {
-			height = l.Height()
+	scale := menu.ContentScale
+	padding := menu.padding().Mul(scale)
+	hoverPadding := menu.Defaults.HoverPadding.Mul(scale)
+	minWidth, minHeight := menu.minimumSize()
+	maxWidth, maxHeight := menu.maximumSize()
+
+	// text is only rescaled while the menu is, or has to be, shrunk so that hovered and focused labels keep their size
+	rescaled := menu.textFactor != 0
+	if rescaled {
+		menu.shrinkText(1)
+		menu.textFactor = 0
+	}
+	if menu.Overflow == OverflowShrink {
+		wTotal, hTotal, _ := menu.contentSize()
+		factor := float32(1)
+		if maxWidth > 0 && wTotal+padding.X()*2 > maxWidth {
+			factor = (maxWidth - padding.X()*2) / wTotal
 		}
-		if l.Width() > width {
-			width = l.Width()
+		if maxHeight > 0 && hTotal+padding.Y()*2 > maxHeight {
+			if f := (maxHeight - padding.Y()*2) / hTotal; f < factor {
+				factor = f
+			}
 		}
-		if l.Width() > wTotal {
-			wTotal = l.Width() + l.GetPadding().Y*2
+		if factor < 1 && factor > 0 {
+			menu.shrinkText(factor)
+			menu.textFactor = factor
+			rescaled = true
 		}
-		borders += l.GetPadding().Y * 2
 	}
-	hTotal = height*float32(length) + borders
+	if rescaled {
+		menu.keepHighlights()
+	}
+	wTotal, hTotal, height := menu.contentSize()
 
-	// readjust entire menu size to hold all objects
-	if menu.Height < hTotal+menu.Defaults.Padding.Y() {
-		menu.Height = hTotal + menu.Defaults.Padding.Y()*2
+	// readjust entire menu size to hold all objects without going beyond the maximum size
+	menu.Width = wTotal + padding.X()*2
+	menu.Height = hTotal + padding.Y()*2
+	if menu.Width < minWidth {
+		menu.Width = minWidth
+	}
+	if menu.Height < minHeight {
+		menu.Height = minHeight
+	}
+	if maxWidth > 0 && menu.Width > maxWidth {
+		menu.Width = maxWidth
 	}
-	if menu.Width < wTotal+menu.Defaults.Padding.X() {
-		menu.Width = wTotal + menu.Defaults.Padding.X()*2
+	if maxHeight > 0 && menu.Height > maxHeight {
+		menu.Height = maxHeight
+	}
+	visibleWidth := menu.Width - padding.X()*2
+	visibleHeight := menu.Height - padding.Y()*2
+	menu.overflowing = wTotal > visibleWidth || hTotal > visibleHeight
+
+	// only scrolling menus keep an offset and it may never reveal more than the content itself
+	maxScroll := hTotal - visibleHeight
+	if menu.Overflow != OverflowScroll || maxScroll < 0 {
+		maxScroll = 0
+	}
+	if menu.scrollOffset > maxScroll {
+		menu.scrollOffset = maxScroll
+	}
+	if menu.scrollOffset < 0 {
+		menu.scrollOffset = 0
 	}
 
 	// calculate an appropriate offset based on the screen position that was requested
-	ww, wh := menu.Window.GetSize()
-	menu.WindowWidth = float32(ww)
-	menu.WindowHeight = float32(wh)
-	switch menu.ScreenPosition {
-	case ScreenTopLeft:
-		menu.screenPositionOffset[0] = -(menu.WindowWidth/2 - menu.Width/2) + ScreenPadding
-		menu.screenPositionOffset[1] = +(menu.WindowHeight/2 - menu.Height/2) - ScreenPadding
-	case ScreenLeft:
-		menu.screenPositionOffset[0] = -(menu.WindowWidth/2 - menu.Width/2) + ScreenPadding
-		menu.screenPositionOffset[1] = 0
-	case ScreenLowerLeft:
-		menu.screenPositionOffset[0] = -(menu.WindowWidth/2 - menu.Width/2) + ScreenPadding
-		menu.screenPositionOffset[1] = -(menu.WindowHeight/2 - menu.Height/2) + ScreenPadding
-	case ScreenTopCenter:
-		menu.screenPositionOffset[0] = 0
-		menu.screenPositionOffset[1] = +(menu.WindowHeight/2 - menu.Height/2) - ScreenPadding
-	case ScreenLowerCenter:
-		menu.screenPositionOffset[0] = 0
-		menu.screenPositionOffset[1] = -(menu.WindowHeight/2 - menu.Height/2) + ScreenPadding
-	case ScreenTopRight:
-		menu.screenPositionOffset[0] = +(menu.WindowWidth/2 - menu.Width/2) - ScreenPadding
-		menu.screenPositionOffset[1] = +(menu.WindowHeight/2 - menu.Height/2) - ScreenPadding
-	case ScreenRight:
-		menu.screenPositionOffset[0] = +(menu.WindowWidth/2 - menu.Width/2) - ScreenPadding
-		menu.screenPositionOffset[1] = 0
-	case ScreenLowerRight:
-		menu.screenPositionOffset[0] = +(menu.WindowWidth/2 - menu.Width/2) - ScreenPadding
-		menu.screenPositionOffset[1] = -(menu.WindowHeight/2 - menu.Height/2) + ScreenPadding
-	}
-
-	// depending on the number of menu elements a vertically centered menus formatting will differ
-	// - the middle object in a menu with an odd number of objects has value 0 = middleIndex-float32(i)
-	//   or, in the case of even values, the two middle values are just around the center
-	middleIndex := float32(math.Floor(float64(length / 2)))
-	if length%2 == 0 {
-		// even number of objects to vertically align
-		for i, l := range menu.Formatable {
-			vertical := height + l.GetPadding().Y*2
-			yOffset := (middleIndex-float32(i)-1)*vertical + vertical/2
-			xOffset := float32(0)
-			if align != AlignCenter {
-				if l.Type() == FormatableLabel {
-					xOffset = -((menu.Width-menu.Defaults.Padding.X()*2-menu.Defaults.HoverPadding.X())/2 - l.Width()/2)
-				} else {
-					xOffset = -((menu.Width-menu.Defaults.Padding.X()*2)/2 - l.Width()/2)
-				}
-				if align == AlignRight {
-					xOffset = -xOffset
-				}
+	menu.screenPositionOffset = anchorPosition(menu.ScreenPosition, menu.ScreenOffset, menu.FramebufferWidth, menu.FramebufferHeight, menu.Width, menu.Height, scale)
+
+	// elements are stacked from the top down
+	// - content that fits is vertically centered within the menu
+	// - content that overflows starts at the top of the visible area, shifted by the scroll offset
+	top := hTotal / 2
+	if hTotal > visibleHeight {
+		top = visibleHeight/2 + menu.scrollOffset
+	}
+	for _, l := range menu.Formatable {
+		vertical := rowHeight(l, height) + l.GetPadding().Y*scale*2
+		yOffset := top - vertical/2
+		top -= vertical
+
+		xOffset := float32(0)
+		if align != AlignCenter && l.Type() != FormatableSeparator {
+			if l.Type() == FormatableLabel {
+				xOffset = -((menu.Width-padding.X()*2-hoverPadding.X())/2 - l.Width()/2)
+			} else {
+				xOffset = -((menu.Width-padding.X()*2)/2 - l.Width()/2)
 			}
-			l.SetPosition(mgl32.Vec2{xOffset + l.GetPosition().X() + menu.screenPositionOffset.X(), yOffset + l.GetPosition().Y() + menu.screenPositionOffset.Y()})
-		}
-	} else {
-		for i, l := range menu.Formatable {
-			vertical := height + l.GetPadding().Y*2
-			yOffset := (middleIndex - float32(i)) * vertical
-			xOffset := float32(0)
-			if align != AlignCenter {
-				if l.Type() == FormatableLabel {
-					xOffset = -((menu.Width-menu.Defaults.Padding.X()*2-menu.Defaults.HoverPadding.X())/2 - l.Width()/2)
-				} else {
-					xOffset = -((menu.Width-menu.Defaults.Padding.X()*2)/2 - l.Width()/2)
-				}
-				if align == AlignRight {
-					xOffset = -xOffset
-				}
+			if align == AlignRight {
+				xOffset = -xOffset
 			}
-			l.SetPosition(mgl32.Vec2{xOffset + l.GetPosition().X() + menu.screenPositionOffset.X(), yOffset + l.GetPosition().Y() + menu.screenPositionOffset.Y()})
+		}
+		l.SetPosition(mgl32.Vec2{xOffset + menu.screenPositionOffset.X(), yOffset + menu.screenPositionOffset.Y()})
+	}
+}
+
+// contentSize measures the space required by all elements including their own padding.
+// Labels and textboxes are given rows as tall as the tallest of them, see rowHeight.
+func (menu *Menu) contentSize() (width, height, tallest float32) {
+	scale := menu.ContentScale
+	for _, l := range menu.Formatable {
+		if l.Type() != FormatableHeader && l.Type() != FormatableSeparator && l.Height() > tallest {
+			tallest = l.Height()
+		}
+		if w := l.Width() + l.GetPadding().X*scale*2; w > width {
+			width = w
 		}
 	}
+	for _, l := range menu.Formatable {
+		height += rowHeight(l, tallest) + l.GetPadding().Y*scale*2
+	}
+	return
+}
+
+// rowHeight keeps labels and textboxes evenly spaced while headers and separators only take up their own height
+func rowHeight(l Formatable, tallest float32) float32 {
+	if l.Type() == FormatableHeader || l.Type() == FormatableSeparator {
+		return l.Height()
+	}
+	return tallest
+}
+
+// keepHighlights restores the hover scale of the labels that are hovered or focused after shrinkText
+func (menu *Menu) keepHighlights() {
+	for _, label := range menu.Labels {
+		if label.IsHover || label.focused {
+			label.Text.SetScale(label.Text.ScaleMax)
+		}
+	}
+}
+
+// shrinkText scales the text of every label and textbox by factor, 1 being the natural size
+func (menu *Menu) shrinkText(factor float32) {
+	for _, label := range menu.Labels {
+		label.rescale(factor)
+	}
+	for _, textbox := range menu.TextBoxes {
+		for _, text := range []*v41.Text{textbox.Text, textbox.Placeholder} {
+			text.ScaleMin = factor
+			text.ScaleMax = factor * labelScaleMax
+			text.SetScale(factor)
+		}
+	}
+	for _, header := range menu.Headers {
+		header.setScale(factor)
+	}
+}
+
+// inView reports whether an element can currently be seen, and therefore interacted with,
+// inside of a menu whose content overflows
+func (menu *Menu) inView(f Formatable) bool {
+	if !menu.overflowing {
+		return true
+	}
+	y := f.GetPosition().Y() - menu.screenPositionOffset.Y()
+	visibleHalf := menu.Height/2 - menu.padding().Y()*menu.ContentScale
+	return y <= visibleHalf && y >= -visibleHalf
+}
+
+// scrollIntoView scrolls the menu just enough for the element to be completely visible
+func (menu *Menu) scrollIntoView(f Formatable) {
+	if menu.Overflow != OverflowScroll || !menu.overflowing {
+		return
+	}
+	y := f.GetPosition().Y() - menu.screenPositionOffset.Y()
+	half := f.Height() / 2
+	visibleHalf := menu.Height/2 - menu.padding().Y()*menu.ContentScale
+	if y+half > visibleHalf {
+		menu.scrollOffset -= y + half - visibleHalf
+	} else if y-half < -visibleHalf {
+		menu.scrollOffset += -visibleHalf - (y - half)
+	} else {
+		return
+	}
+	menu.layout()
+}
+
+// Scroll moves the content of a menu using OverflowScroll by delta logical pixels.
+// Positive values reveal content further down the menu.
+func (menu *Menu) Scroll(delta float32) {
+	if menu.Overflow != OverflowScroll || !menu.isFinalized {
+		return
+	}
+	menu.scrollOffset += delta * menu.ContentScale
+	menu.layout()
 }
 
 // NewTextBox handles vertical spacing
@@ -396,31 +594,231 @@ func (menu *Menu) NewTextBox(str string, width, height float32, borderWidth int3
 	textbox := &TextBox{}
 	textbox.Load(menu, width, height, borderWidth)
 	textbox.SetString(str)
-	textbox.SetColor(menu.Defaults.TextColor)
+	textbox.SetColor(textbox.style(StyleNormal).TextColor)
 	textbox.Text.SetScale(1)
+	textbox.Placeholder.SetScale(1)
 
 	menu.TextBoxes = append(menu.TextBoxes, textbox)
 	menu.Formatable = append(menu.Formatable, textbox)
+	menu.relayout()
 	return textbox
 }
 
+// NewHeader adds a non-interactive group title drawn using MenuDefaults.HeaderColor and HeaderScale
+func (menu *Menu) NewHeader(str string, padding Padding) *Header {
+	header := &Header{
+		Menu:    menu,
+		Text:    v41.NewText(menu.Font, 1.0, 1.0),
+		Padding: padding,
+	}
+	menu.Headers = append(menu.Headers, header)
+	menu.Formatable = append(menu.Formatable, header)
+
+	header.SetString(str)
+	header.setScale(1)
+	header.Text.SetColor(menu.Defaults.HeaderColor)
+	menu.relayout()
+	return header
+}
+
+// NewSeparator adds a horizontal rule of the given logical thickness drawn using MenuDefaults.SeparatorColor
+func (menu *Menu) NewSeparator(thickness float32, padding Padding) *Separator {
+	separator := &Separator{
+		Menu:      menu,
+		Thickness: thickness,
+		Padding:   padding,
+		Color:     menu.Defaults.SeparatorColor,
+	}
+	separator.load()
+	menu.Separators = append(menu.Separators, separator)
+	menu.Formatable = append(menu.Formatable, separator)
+	menu.relayout()
+	return separator
+}
+
+// Remove takes an element out of the menu and releases its gl resources
+func (menu *Menu) Remove(f Formatable) {
+	index := menu.IndexOf(f)
+	if index < 0 {
+		return
+	}
+	menu.forget(f)
+	formatable := make([]Formatable, 0, len(menu.Formatable)-1)
+	formatable = append(formatable, menu.Formatable[:index]...)
+	menu.Formatable = append(formatable, menu.Formatable[index+1:]...)
+	menu.sortElements()
+	release(f)
+	menu.relayout()
+}
+
+// Clear removes every element from the menu, ie before rebuilding a list that changes at runtime
+func (menu *Menu) Clear() {
+	formatable := menu.Formatable
+	for _, f := range formatable {
+		menu.forget(f)
+	}
+	menu.Formatable = nil
+	menu.sortElements()
+	for _, f := range formatable {
+		release(f)
+	}
+	menu.relayout()
+}
+
+// forget drops the focus and the pending mouse click held by an element that is leaving the menu
+func (menu *Menu) forget(f Formatable) {
+	if menu.focused == f {
+		menu.Blur()
+	}
+	if menu.MenuManager != nil && menu.MenuManager.pressed == menu {
+		menu.MenuManager.pressed = nil
+	}
+}
+
+// Move places an element at the given index, shifting the elements after it down the menu
+func (menu *Menu) Move(f Formatable, index int) {
+	from := menu.IndexOf(f)
+	if from < 0 {
+		return
+	}
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(menu.Formatable) {
+		index = len(menu.Formatable) - 1
+	}
+	formatable := append([]Formatable(nil), menu.Formatable[:from]...)
+	formatable = append(formatable, menu.Formatable[from+1:]...)
+	formatable = append(formatable, nil)
+	copy(formatable[index+1:], formatable[index:])
+	formatable[index] = f
+	menu.Formatable = formatable
+	menu.sortElements()
+	menu.relayout()
+}
+
+// IndexOf returns the position of the element within the menu or -1 when it isn't part of the menu
+func (menu *Menu) IndexOf(f Formatable) int {
+	for i := range menu.Formatable {
+		if menu.Formatable[i] == f {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortElements rebuilds the per type element lists so that they follow the order of Formatable.
+// New lists are allocated since the old ones may still be iterated by whatever removed the element.
+func (menu *Menu) sortElements() {
+	menu.Labels = make([]*Label, 0, len(menu.Labels))
+	menu.TextBoxes = make([]*TextBox, 0, len(menu.TextBoxes))
+	menu.Headers = make([]*Header, 0, len(menu.Headers))
+	menu.Separators = make([]*Separator, 0, len(menu.Separators))
+	for _, f := range menu.Formatable {
+		switch element := f.(type) {
+		case *Label:
+			menu.Labels = append(menu.Labels, element)
+		case *TextBox:
+			menu.TextBoxes = append(menu.TextBoxes, element)
+		case *Header:
+			menu.Headers = append(menu.Headers, element)
+		case *Separator:
+			menu.Separators = append(menu.Separators, element)
+		}
+	}
+
+	// keyboard navigation indexes are no longer meaningful unless they follow the focus
+	menu.NavigationVia = NavigationMouse
+	menu.NavigationIndex = -1
+	if menu.focused != nil {
+		if index := menu.IndexOf(menu.focused); index >= 0 {
+			menu.NavigationVia = NavigationKey
+			menu.NavigationIndex = index
+		} else {
+			menu.focused = nil
+		}
+	}
+}
+
+// relayout updates a finalized menu after its elements have changed.  Unfinalized menus are laid out by Finalize.
+func (menu *Menu) relayout() {
+	if menu.isFinalized {
+		menu.layout()
+	}
+}
+
+// minimumSize resolves the configured menu dimensions, in framebuffer pixels, against the current window size
+func (menu *Menu) minimumSize() (width, height float32) {
+	width, height = menu.Defaults.Dimensions.X()*menu.ContentScale, menu.Defaults.Dimensions.Y()*menu.ContentScale
+	if !menu.Defaults.Size.Width.IsZero() {
+		width = menu.Defaults.Size.Width.pixels(menu.FramebufferWidth, menu.ContentScale)
+	}
+	if !menu.Defaults.Size.Height.IsZero() {
+		height = menu.Defaults.Size.Height.pixels(menu.FramebufferHeight, menu.ContentScale)
+	}
+	if !menu.MinSize.Width.IsZero() {
+		width = menu.MinSize.Width.pixels(menu.FramebufferWidth, menu.ContentScale)
+	}
+	if !menu.MinSize.Height.IsZero() {
+		height = menu.MinSize.Height.pixels(menu.FramebufferHeight, menu.ContentScale)
+	}
+	return
+}
+
+// maximumSize resolves MaxSize in framebuffer pixels.  Zero means unbounded.
+func (menu *Menu) maximumSize() (width, height float32) {
+	width = menu.MaxSize.Width.pixels(menu.FramebufferWidth, menu.ContentScale)
+	height = menu.MaxSize.Height.pixels(menu.FramebufferHeight, menu.ContentScale)
+	return
+}
+
+// SetAnchor places the menu relative to one of the ScreenPosition anchors.
+// The offset may mix pixels and window percentages, ie Offset{X: Pct(-5), Y: Px(-40)}.
+// Takes effect during Finalize or the next Resize.
+func (menu *Menu) SetAnchor(anchor ScreenPosition, offset Offset) {
+	menu.ScreenPosition = anchor
+	menu.ScreenOffset = offset
+}
+
 func (menu *Menu) Show() {
+	menu.show(TransitionNone)
+}
+
+func (menu *Menu) show(via Transition) {
 	for i := range menu.Labels {
 		menu.Labels[i].Reset()
 		menu.NavigationVia = NavigationMouse
 		menu.NavigationIndex = -1
 	}
 	menu.IsVisible = true
+	if menu.MenuManager != nil {
+		menu.MenuManager.setVisible(menu, true)
+	}
 	if menu.OnShow != nil {
 		menu.OnShow()
 	}
+	if menu.OnShowVia != nil {
+		menu.OnShowVia(via)
+	}
 }
 
 func (menu *Menu) Hide() {
+	menu.hide(TransitionNone)
+}
+
+func (menu *Menu) hide(via Transition) {
+	menu.setFocus(nil)
 	for i := range menu.Labels {
 		menu.Labels[i].Reset()
 	}
+	wasVisible := menu.IsVisible
 	menu.IsVisible = false
+	if menu.MenuManager != nil {
+		menu.MenuManager.setVisible(menu, false)
+	}
+	if wasVisible && menu.OnHide != nil {
+		menu.OnHide(via)
+	}
 }
 
 func (menu *Menu) Toggle() {
@@ -428,36 +826,47 @@ func (menu *Menu) Toggle() {
 		menu.Labels[i].Reset()
 	}
 	menu.IsVisible = !menu.IsVisible
+	if menu.MenuManager != nil {
+		menu.MenuManager.setVisible(menu, menu.IsVisible)
+	}
+}
+
+// Contains reports whether the window coordinates, as given by glfw's GetCursorPos, lie within the menu background
+func (menu *Menu) Contains(xPos, yPos float64) bool {
+	yPos = float64(menu.WindowHeight) - yPos
+	X1 := menu.orthoToScreen(menu.screenPositionOffset.X()-menu.Width/2, menu.screenPositionOffset.Y()-menu.Height/2)
+	X2 := menu.orthoToScreen(menu.screenPositionOffset.X()+menu.Width/2, menu.screenPositionOffset.Y()+menu.Height/2)
+	return float32(xPos) >= X1.X && float32(xPos) <= X2.X && float32(yPos) >= X1.Y && float32(yPos) <= X2.Y
+}
+
+// clearHover removes hover highlighting, ie when the cursor has moved onto another menu
+func (menu *Menu) clearHover() {
+	for i := range menu.Labels {
+		menu.Labels[i].IsHover = false
+	}
 }
 
 // NewMenu creates a new menu object with a background centered on the screen or positioned using offsetBy
 func NewMenu(window *glfw.Window, name string, font *v41.Font, defaults MenuDefaults, screenPosition ScreenPosition) (*Menu, error) {
-	// i believe we are actually supposed to pass in the framebuffer sizes when creating the orthographic projection
-	// this would probably require some changes though in order to track mouse movement.
 	width, height := window.GetSize()
 	menu := &Menu{
 		Name:           name,
 		Defaults:       defaults,
 		Font:           font,
 		IsVisible:      false,
-		ShowOnKey:      glfw.KeyM,
+		IsModal:        true,
+		ShowOnKey:      glfw.KeyUnknown,
+		ScrollStep:     20,
 		Width:          defaults.Dimensions.X(),
 		Height:         defaults.Dimensions.Y(),
 		Window:         window,
 		ScreenPosition: screenPosition,
+		ScreenOffset:   DefaultOffset(screenPosition),
 	}
 	menu.ResizeWindow(float32(width), float32(height))
 
 	// reasonable default is to follow the first followable element when hitting enter i suppose
-	menu.OnEnterRelease = func() {
-		if menu.IsVisible {
-			for i := range menu.Formatable {
-				if menu.Formatable[i].Follow() {
-					return
-				}
-			}
-		}
-	}
+	menu.OnEnterRelease = menu.followFocus
 
 	// create shader program and define attributes and uniforms
 	var err error
@@ -514,10 +923,38 @@ func NewMenu(window *glfw.Window, name string, font *v41.Font, defaults MenuDefa
 	return menu, nil
 }
 
+// ResizeWindow accepts the logical window size and looks up the matching framebuffer size
 func (menu *Menu) ResizeWindow(width float32, height float32) {
+	fbWidth, fbHeight := width, height
+	if menu.Window != nil {
+		w, h := menu.Window.GetFramebufferSize()
+		fbWidth, fbHeight = float32(w), float32(h)
+	}
+	menu.ResizeFramebuffer(width, height, fbWidth, fbHeight)
+}
+
+// ResizeFramebuffer sets both the logical window size, in which layout values and cursor positions are
+// expressed, and the framebuffer size that the orthographic projection renders into
+func (menu *Menu) ResizeFramebuffer(width, height, fbWidth, fbHeight float32) {
 	menu.WindowWidth = width
 	menu.WindowHeight = height
-	menu.Font.ResizeWindow(width, height)
+	menu.FramebufferWidth = fbWidth
+	menu.FramebufferHeight = fbHeight
+	menu.ContentScale = 1
+	if width > 0 && fbWidth > 0 {
+		menu.ContentScale = fbWidth / width
+	}
+	menu.Font.ResizeWindow(fbWidth, fbHeight)
+}
+
+// Resize accepts the framebuffer size and, once the menu has been finalized, recomputes the layout
+// so that anchored menus and their elements stay in place
+func (menu *Menu) Resize(fbWidth float32, fbHeight float32) {
+	width, height := menu.Window.GetSize()
+	menu.ResizeFramebuffer(float32(width), float32(height), fbWidth, fbHeight)
+	if menu.isFinalized {
+		menu.layout()
+	}
 }
 
 func (menu *Menu) makeBufferData() {
@@ -548,32 +985,55 @@ func (menu *Menu) makeBufferData() {
 func (menu *Menu) Release() {
 	gl.DeleteBuffers(1, &menu.vbo)
 	gl.DeleteBuffers(1, &menu.ebo)
-	gl.DeleteBuffers(1, &menu.vao)
-	for i := range menu.Labels {
-		menu.Labels[i].Text.Release()
+	gl.DeleteVertexArrays(1, &menu.vao)
+	gl.DeleteProgram(menu.program)
+	if menu.focusVao != 0 {
+		gl.DeleteBuffers(1, &menu.focusVbo)
+		gl.DeleteBuffers(1, &menu.focusEbo)
+		gl.DeleteVertexArrays(1, &menu.focusVao)
 	}
-	for i := range menu.TextBoxes {
-		menu.TextBoxes[i].Text.Release()
+	for i := range menu.Formatable {
+		release(menu.Formatable[i])
 	}
 }
 
 func (menu *Menu) Draw() bool {
-	if !menu.MenuManager.IsFinalized {
+	if menu.MenuManager != nil && !menu.MenuManager.IsFinalized {
 		panic("A menu manager must be finalized prior to drawing!")
 	}
 	if !menu.IsVisible {
 		return menu.IsVisible
 	}
+	menu.refreshBindings()
+	menu.refreshProviders(time.Now())
 	gl.UseProgram(menu.program)
 
+	if menu.DimColor.W() > 0 {
+		scale := menu.dimmed()
+		center := mgl32.Vec2{}
+		gl.UniformMatrix4fv(menu.scaleUniform, 1, false, &scale[0])
+		gl.Uniform4fv(menu.backgroundUniform, 1, &menu.DimColor[0])
+		gl.Uniform2fv(menu.finalPositionUniform, 1, &center[0])
+		gl.UniformMatrix4fv(menu.orthographicUniform, 1, false, &menu.Font.OrthographicMatrix[0])
+
+		gl.Enable(gl.BLEND)
+		gl.BlendEquation(gl.FUNC_ADD)
+		gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+		gl.BindVertexArray(menu.vao)
+		gl.DrawElements(gl.TRIANGLES, int32(menu.eboIndexCount), gl.UNSIGNED_INT, nil)
+		gl.BindVertexArray(0)
+		gl.Disable(gl.BLEND)
+	}
+
+	style := menu.style(StyleNormal)
 	for i := 0; i < 2; i++ {
 		// i == 0 is background draw at higher scale, producing a border around the menu
 		if i == 0 {
 			gl.UniformMatrix4fv(menu.scaleUniform, 1, false, &menu.scaleMatrix[0])
-			gl.Uniform4fv(menu.backgroundUniform, 1, &menu.Defaults.BorderColor[0])
+			gl.Uniform4fv(menu.backgroundUniform, 1, &style.BorderColor[0])
 		} else {
 			gl.UniformMatrix4fv(menu.scaleUniform, 1, false, &menu.scaleIdent4[0])
-			gl.Uniform4fv(menu.backgroundUniform, 1, &menu.Defaults.BackgroundColor[0])
+			gl.Uniform4fv(menu.backgroundUniform, 1, &style.BackgroundColor[0])
 		}
 		gl.Uniform2fv(menu.finalPositionUniform, 1, &menu.finalPosition[0])
 		gl.UniformMatrix4fv(menu.orthographicUniform, 1, false, &menu.Font.OrthographicMatrix[0])
@@ -588,16 +1048,43 @@ func (menu *Menu) Draw() bool {
 		gl.Disable(gl.BLEND)
 	}
 
-	for i := range menu.Labels {
-		if !menu.Labels[i].IsHover {
-			if menu.Labels[i].OnNotHover != nil {
-				menu.Labels[i].OnNotHover()
+	// content that doesn't fit is clipped to the menu background
+	if menu.overflowing {
+		gl.Enable(gl.SCISSOR_TEST)
+		gl.Scissor(
+			int32(menu.FramebufferWidth/2+menu.screenPositionOffset.X()-menu.Width/2),
+			int32(menu.FramebufferHeight/2+menu.screenPositionOffset.Y()-menu.Height/2),
+			int32(menu.Width),
+			int32(menu.Height),
+		)
+	}
+	if len(menu.Separators) > 0 {
+		gl.Enable(gl.BLEND)
+		for _, separator := range menu.Separators {
+			separator.Draw()
+		}
+		gl.Disable(gl.BLEND)
+	}
+	// callbacks may remove elements, which replaces the element lists rather than changing the ones being drawn
+	for _, header := range menu.Headers {
+		header.Draw()
+	}
+	for _, label := range menu.Labels {
+		if !label.IsHover && !label.disabled && !label.focused {
+			if label.OnNotHover != nil {
+				label.OnNotHover()
 			}
 		}
-		menu.Labels[i].Draw()
+		label.Draw()
 	}
-	for i := range menu.TextBoxes {
-		menu.TextBoxes[i].Draw()
+	for _, textbox := range menu.TextBoxes {
+		textbox.Draw()
+	}
+	if menu.focused != nil {
+		menu.drawFocus()
+	}
+	if menu.overflowing {
+		gl.Disable(gl.SCISSOR_TEST)
 	}
 	return menu.IsVisible
 }
@@ -608,10 +1095,23 @@ func (menu *Menu) MouseClick(xPos, yPos float64, button MouseClick) {
 	}
 	yPos = float64(menu.WindowHeight) - yPos
 	for i := range menu.Labels {
-		menu.Labels[i].IsClicked(xPos, yPos, button)
+		if menu.inView(menu.Labels[i]) {
+			menu.Labels[i].IsClicked(xPos, yPos, button)
+		}
 	}
 	for i := range menu.TextBoxes {
-		menu.TextBoxes[i].IsClicked(xPos, yPos, button)
+		if menu.inView(menu.TextBoxes[i]) {
+			menu.TextBoxes[i].IsClicked(xPos, yPos, button)
+		} else {
+			menu.TextBoxes[i].IsEdit = false
+		}
+		if menu.TextBoxes[i].IsClick {
+			menu.Focus(menu.TextBoxes[i])
+		}
+	}
+	// clicking outside of the focused textbox ends its edit
+	if textbox, ok := menu.focused.(*TextBox); ok && !textbox.IsEdit && !textbox.IsClick {
+		menu.Blur()
 	}
 }
 
@@ -620,11 +1120,11 @@ func (menu *Menu) MouseRelease(xPos, yPos float64, button MouseClick) {
 		return
 	}
 	yPos = float64(menu.WindowHeight) - yPos
-	for i := range menu.Labels {
-		menu.Labels[i].IsReleased(xPos, yPos, button)
+	for _, label := range menu.Labels {
+		label.IsReleased(xPos, yPos, button)
 	}
-	for i := range menu.TextBoxes {
-		menu.TextBoxes[i].IsReleased(xPos, yPos, button)
+	for _, textbox := range menu.TextBoxes {
+		textbox.IsReleased(xPos, yPos, button)
 	}
 }
 
@@ -635,23 +1135,37 @@ func (menu *Menu) MouseHover(xPos, yPos float64) {
 	dist := math.Sqrt(math.Pow(float64(menu.LastMousePosition[0])-xPos, 2) + math.Pow(float64(menu.LastMousePosition[1])-yPos, 2))
 	menu.LastMousePosition[0] = float32(xPos)
 	menu.LastMousePosition[1] = float32(yPos)
-	if dist > 1 {
-		// a bit of mouse movement will reenable mouse position evaluation
+	if dist > 1 && menu.NavigationVia == NavigationKey {
+		// a bit of mouse movement will reenable mouse position evaluation.  Textboxes keep the focus while they are edited.
 		menu.NavigationVia = NavigationMouse
 		menu.NavigationIndex = -1
+		if _, ok := menu.focused.(*TextBox); !ok {
+			menu.Blur()
+		}
 	}
 	if menu.NavigationVia == NavigationKey {
-		menu.Formatable[menu.NavigationIndex].NavigateTo()
 		return
 	}
 	yPos = float64(menu.WindowHeight) - yPos
 	for i := range menu.Labels {
 		if menu.Labels[i].OnHover != nil {
-			menu.Labels[i].IsHovered(xPos, yPos)
+			if menu.inView(menu.Labels[i]) {
+				menu.Labels[i].IsHovered(xPos, yPos)
+			} else {
+				menu.Labels[i].IsHover = false
+			}
 		}
 	}
 }
 
+// orthoToScreen converts a point in the orthographic projection, measured in framebuffer pixels from
+// the center of the window, into logical window coordinates measured from the lower left corner
+func (menu *Menu) orthoToScreen(x, y float32) (p Point) {
+	p.X = (x + menu.FramebufferWidth/2) / menu.ContentScale
+	p.Y = (y + menu.FramebufferHeight/2) / menu.ContentScale
+	return
+}
+
 func (menu *Menu) findCenter() (lowerLeft Point) {
 	menuWidthHalf := menu.Width / 2
 	menuHeightHalf := menu.Height / 2
@@ -661,11 +1175,32 @@ func (menu *Menu) findCenter() (lowerLeft Point) {
 	return
 }
 
+// followFocus follows the focused element, or else the first element that can be followed
+func (menu *Menu) followFocus() {
+	if !menu.IsVisible {
+		return
+	}
+	if menu.focused != nil && menu.focused.Follow() {
+		return
+	}
+	for i := range menu.Formatable {
+		if menu.Formatable[i].Follow() {
+			return
+		}
+	}
+}
+
 func (menu *Menu) KeyRelease(key glfw.Key, withShift bool) {
 	if key == glfw.KeyUp || key == glfw.KeyDown {
-		for i := range menu.Formatable {
-			if menu.Formatable[i].NavigateAway() {
-				menu.NavigationIndex = i
+		// start from the focused element, or a label hovered by the mouse, without clearing its highlight
+		// since Focus leaves it as it is when the endpoints below land on it again
+		if menu.focused != nil {
+			menu.NavigationIndex = menu.IndexOf(menu.focused)
+		} else {
+			for _, label := range menu.Labels {
+				if label.IsHover {
+					menu.NavigationIndex = menu.IndexOf(label)
+				}
 			}
 		}
 		// adjust endpoints skipping objects that are NOOP
@@ -710,15 +1245,36 @@ func (menu *Menu) KeyRelease(key glfw.Key, withShift bool) {
 		}
 
 		// perform necessary visual changes as we navigate to the next place
-		for i := range menu.Formatable {
-			if i == menu.NavigationIndex {
-				menu.Formatable[i].NavigateTo()
-			}
+		if menu.NavigationIndex >= 0 && menu.NavigationIndex < len(menu.Formatable) {
+			menu.Focus(menu.Formatable[menu.NavigationIndex])
 		}
 		menu.NavigationVia = NavigationKey
 	}
-	for i := range menu.TextBoxes {
-		menu.TextBoxes[i].KeyRelease(key, withShift)
+	if key == glfw.KeyTab {
+		if withShift {
+			menu.FocusPrevious()
+		} else {
+			menu.FocusNext()
+		}
+		return
+	}
+	if key == glfw.KeyEnter && menu.EnterAdvances {
+		if textbox, ok := menu.focused.(*TextBox); ok && textbox.IsEdit {
+			menu.FocusNext()
+			return
+		}
+	}
+	if key == glfw.KeyLeft || key == glfw.KeyRight {
+		if label, ok := menu.focused.(*Label); ok && label.adjust != nil && !label.disabled {
+			if key == glfw.KeyLeft {
+				label.adjust(-1)
+			} else {
+				label.adjust(+1)
+			}
+		}
+	}
+	for _, textbox := range menu.TextBoxes {
+		textbox.KeyRelease(key, withShift)
 	}
 	if menu.OnEnterRelease != nil && key == glfw.KeyEnter {
 		menu.OnEnterRelease()
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"github.com/4ydx/gltext/v4.1"
 	"github.com/go-gl/glfw/v3.2/glfw"
+	"github.com/go-gl/mathgl/mgl32"
+	"sort"
 )
 
 type MenuManager struct {
@@ -13,31 +15,45 @@ type MenuManager struct {
 	StartMenu   string   // the name passed to each NewMenu call
 	Menus       map[string]*Menu
 	IsFinalized bool
+
+	DisableHotkeys   bool   // ignore StartKey and ShowOnKey, ie while gameplay has its own use for them
+	RequireReachable bool   // Finalize fails when a menu can't be reached, see Validate
+	Theme            *Theme // shared by every menu.  Use SetTheme once menus have elements.
+	Localizer        *Localizer
+
+	// navigation stack, the last entry being the menu currently shown
+	history []*Menu
+
+	// visible menus ordered from bottom to top
+	visible []*Menu
+	pressed *Menu      // menu that received the last mouse click
+	cursor  mgl32.Vec2 // last hover position
+
+	// dialogs
+	DialogDefaults MenuDefaults // when unset dialogs borrow the StartMenu defaults
+	DimColor       mgl32.Vec4   // drawn over the whole window beneath a dialog.  Defaults to translucent black.
+	window         *glfw.Window
+	dialogCount    int
+	dialogs        []*Menu // open dialogs, which are kept apart from Menus
+	released       []*Menu // closed dialogs waiting to release their gl resources
+
+	Notifications *Notifications
 }
 
-// Finalize connects menus together and performs final formatting steps
-// this must be run after all menus are prepared
+// Finalize performs final formatting steps
+// this must be run after all menus are prepared.  Menus created afterwards are finalized individually
+// and GOTO_MENU labels find their target menus when clicked.
+// Nothing is finalized when Validate reports a problem with the menu graph.  Unreachable menus are only
+// warnings, unless RequireReachable is set, and the menus are finalized regardless.
 func (mm *MenuManager) Finalize(align Alignment) error {
 	if mm.IsFinalized {
 		return errors.New("Menus have already been finalized")
 	}
+	if err := mm.Validate(); err != nil {
+		return err
+	}
 	for _, menu := range mm.Menus {
 		menu.Finalize(align)
-		for _, label := range menu.Labels {
-			if label.Config.Action == GOTO_MENU {
-				gotoMenu, ok := mm.Menus[label.Config.Goto]
-				if ok {
-					func(from *Menu, to *Menu, l *Label) {
-						l.onRelease = func(xPos, yPos float64, button MouseClick, inBox bool) {
-							if inBox {
-								from.Hide()
-								to.Show()
-							}
-						}
-					}(menu, gotoMenu, label)
-				}
-			}
-		}
 	}
 	mm.IsFinalized = true
 	return nil
@@ -49,62 +65,247 @@ func (mm *MenuManager) IsVisible() bool {
 			return true
 		}
 	}
-	return false
+	return len(mm.dialogs) > 0
 }
 
 // Clicked resolves menus that have been clicked
+// The click is delivered to the topmost menu under the cursor.  Modal menus receive every click
+// and prevent menus beneath them from seeing it.
 func (mm *MenuManager) MouseClick(xPos, yPos float64, button MouseClick) {
-	for _, menu := range mm.Menus {
-		if menu.IsVisible {
-			menu.MouseClick(xPos, yPos, button)
-			return
-		}
+	mm.pressed = mm.mouseTarget(xPos, yPos)
+	if mm.pressed != nil {
+		mm.pressed.MouseClick(xPos, yPos, button)
 	}
 }
 
+// MouseRelease is delivered to the menu that received the matching click
 func (mm *MenuManager) MouseRelease(xPos, yPos float64, button MouseClick) {
-	for _, menu := range mm.Menus {
-		if menu.IsVisible {
-			menu.MouseRelease(xPos, yPos, button)
-			return
-		}
+	menu := mm.pressed
+	mm.pressed = nil
+	if menu == nil || !menu.IsVisible {
+		menu = mm.mouseTarget(xPos, yPos)
+	}
+	if menu != nil {
+		menu.MouseRelease(xPos, yPos, button)
 	}
 }
 
 func (mm *MenuManager) MouseHover(xPos, yPos float64) {
-	for _, menu := range mm.Menus {
-		if menu.IsVisible {
+	mm.cursor = mgl32.Vec2{float32(xPos), float32(yPos)}
+	target := mm.mouseTarget(xPos, yPos)
+	for _, menu := range mm.visible {
+		if menu == target {
 			menu.MouseHover(xPos, yPos)
-			return
+		} else {
+			menu.clearHover()
 		}
 	}
 }
 
+// MouseScroll scrolls the menu under the cursor.  Suitable for calling from glfw's scroll callback.
+func (mm *MenuManager) MouseScroll(xOffset, yOffset float64) {
+	menu := mm.mouseTarget(float64(mm.cursor.X()), float64(mm.cursor.Y()))
+	if menu != nil {
+		menu.Scroll(-float32(yOffset) * menu.ScrollStep)
+	}
+}
+
+// KeyRelease passes keys on to the topmost modal menu, or the topmost menu when none are modal.
+// Escape calls the target menu's OnCancel or, when unset, navigates back through the menu history.
+// StartKey opens the StartMenu when no modal menu is visible and otherwise backs out like escape, hiding the
+// topmost modal menu when there is nothing to go back to.
+// A menu's ShowOnKey toggles that menu.  Tab, enter and the arrow keys can't be used as hotkeys.
+// Textboxes being edited receive every key themselves, hotkeys included.
 func (mm *MenuManager) KeyRelease(key glfw.Key, withShift bool) {
-	for _, menu := range mm.Menus {
-		if menu.IsVisible {
-			menu.KeyRelease(key, withShift)
+	if !mm.IsEditing() {
+		if key == glfw.KeyEscape && mm.back() {
+			return
+		}
+		if !mm.DisableHotkeys && mm.hotkey(key) {
 			return
 		}
 	}
+	if menu := mm.keyTarget(); menu != nil {
+		menu.KeyRelease(key, withShift)
+	}
 }
 
-func (mm *MenuManager) Draw() bool {
-	for _, menu := range mm.Menus {
-		if menu.IsVisible {
-			if menu.OnComplete != nil {
-				menu.OnComplete()
+// back cancels the topmost menu or navigates back through the history, reporting whether anything happened
+func (mm *MenuManager) back() bool {
+	if menu := mm.keyTarget(); menu != nil && menu.OnCancel != nil {
+		menu.OnCancel()
+		return true
+	}
+	if len(mm.history) > 0 {
+		mm.Pop()
+		return true
+	}
+	return false
+}
+
+// hotkey handles StartKey and ShowOnKey, reporting whether the key was consumed.
+// Navigation keys always reach the menu so that they can't be taken over by a hotkey.
+func (mm *MenuManager) hotkey(key glfw.Key) bool {
+	if key == glfw.KeyUnknown || isNavigationKey(key) {
+		return false
+	}
+	if key == mm.StartKey {
+		if !mm.IsModal() {
+			return mm.Show(mm.StartMenu) == nil
+		}
+		// menus opened with Show have no history to go back through
+		if !mm.back() {
+			mm.keyTarget().Hide()
+		}
+		return true
+	}
+	for _, menu := range mm.sortedMenus() {
+		if menu.ShowOnKey != key {
+			continue
+		}
+		switch {
+		case menu.IsVisible && mm.Current() == menu:
+			mm.Pop()
+		case menu.IsVisible:
+			menu.Hide()
+		case menu.IsModal:
+			if mm.Current() == nil {
+				mm.Show(menu.Name)
+			} else {
+				mm.push(menu, TransitionForward)
 			}
-			return menu.Draw()
+		default:
+			menu.Show()
 		}
+		return true
+	}
+	return false
+}
+
+// isNavigationKey reports whether a menu uses key to move between or activate its elements
+func isNavigationKey(key glfw.Key) bool {
+	switch key {
+	case glfw.KeyTab, glfw.KeyEnter, glfw.KeyUp, glfw.KeyDown, glfw.KeyLeft, glfw.KeyRight:
+		return true
 	}
 	return false
 }
 
+// IsModal reports whether a modal menu is visible, which is typically when gameplay should pause.
+// Non-modal menus, ie a hud, are drawn over the game without pausing it.
+func (mm *MenuManager) IsModal() bool {
+	for _, menu := range mm.visible {
+		if menu.IsModal {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedMenus lists menus by name so that iteration does not depend on map ordering
+func (mm *MenuManager) sortedMenus() []*Menu {
+	names := make([]string, 0, len(mm.Menus))
+	for name := range mm.Menus {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	menus := make([]*Menu, len(names))
+	for i, name := range names {
+		menus[i] = mm.Menus[name]
+	}
+	return menus
+}
+
+// Draw renders every visible menu from the bottom layer up followed by any notifications.
+// Returns true when a menu was drawn.  Use IsModal to tell whether gameplay should pause.
+func (mm *MenuManager) Draw() bool {
+	mm.releaseClosed()
+
+	visible := make([]*Menu, len(mm.visible))
+	copy(visible, mm.visible)
+	for _, menu := range visible {
+		if menu.OnComplete != nil {
+			menu.OnComplete()
+		}
+		menu.Draw()
+	}
+	if mm.Notifications != nil {
+		mm.Notifications.Draw()
+	}
+	return len(visible) > 0
+}
+
+// Visible returns the visible menus ordered from bottom to top
+func (mm *MenuManager) Visible() []*Menu {
+	return mm.visible
+}
+
+// mouseTarget finds the topmost menu under the cursor.  Menus hidden beneath a modal menu are never found.
+func (mm *MenuManager) mouseTarget(xPos, yPos float64) *Menu {
+	for i := len(mm.visible) - 1; i >= 0; i-- {
+		menu := mm.visible[i]
+		if menu.IsModal || menu.Contains(xPos, yPos) {
+			return menu
+		}
+	}
+	return nil
+}
+
+func (mm *MenuManager) keyTarget() *Menu {
+	for i := len(mm.visible) - 1; i >= 0; i-- {
+		if mm.visible[i].IsModal {
+			return mm.visible[i]
+		}
+	}
+	if len(mm.visible) > 0 {
+		return mm.visible[len(mm.visible)-1]
+	}
+	return nil
+}
+
+// setVisible keeps the visible list sorted by Layer.  Within a layer the most recently shown menu is on top.
+func (mm *MenuManager) setVisible(m *Menu, isVisible bool) {
+	for i := range mm.visible {
+		if mm.visible[i] == m {
+			mm.visible = append(mm.visible[:i], mm.visible[i+1:]...)
+			break
+		}
+	}
+	if !isVisible {
+		return
+	}
+	i := len(mm.visible)
+	for i > 0 && mm.visible[i-1].Layer > m.Layer {
+		i--
+	}
+	mm.visible = append(mm.visible, nil)
+	copy(mm.visible[i+1:], mm.visible[i:])
+	mm.visible[i] = m
+}
+
+// Resize lays out every menu again for the new framebuffer dimensions.
+// Suitable for calling from glfw's framebuffer size callback.
+func (mm *MenuManager) Resize(width, height float32) {
+	for _, menu := range mm.Menus {
+		menu.Resize(width, height)
+	}
+	for _, dialog := range mm.dialogs {
+		dialog.Resize(width, height)
+	}
+	if mm.Notifications != nil {
+		mm.Notifications.Resize(width, height)
+	}
+}
+
 func (mm *MenuManager) Release() {
 	for _, menu := range mm.Menus {
 		menu.Release()
 	}
+	mm.closeDialogs()
+	mm.releaseClosed()
+	if mm.Notifications != nil {
+		mm.Notifications.Release()
+	}
 }
 
 func (mm *MenuManager) NewMenu(window *glfw.Window, name string, menuDefaults MenuDefaults, screenPosition ScreenPosition) (*Menu, error) {
@@ -113,6 +314,9 @@ func (mm *MenuManager) NewMenu(window *glfw.Window, name string, menuDefaults Me
 		return nil, err
 	}
 	m.MenuManager = mm
+	if mm.window == nil {
+		mm.window = window
+	}
 
 	if _, ok := mm.Menus[name]; ok {
 		return nil, errors.New(fmt.Sprintf("The named menu %s already exists.", name))
@@ -125,17 +329,98 @@ func (mm *MenuManager) Hide() {
 	for _, m := range mm.Menus {
 		m.Hide()
 	}
+	mm.closeDialogs()
+	mm.history = nil
 }
 
+// Show displays the named menu and makes it the root of the navigation history
 func (mm *MenuManager) Show(name string) error {
 	m, ok := mm.Menus[name]
 	if !ok {
 		return errors.New(fmt.Sprintf("The named menu '%s' doesn't exists.", name))
 	}
+	mm.history = []*Menu{m}
 	m.Show()
 	return nil
 }
 
+// Current returns the menu at the top of the navigation history, nil when the history is empty
+func (mm *MenuManager) Current() *Menu {
+	if len(mm.history) == 0 {
+		return nil
+	}
+	return mm.history[len(mm.history)-1]
+}
+
+// Push hides the current menu and shows the named menu, remembering the way back
+func (mm *MenuManager) Push(name string) error {
+	m, ok := mm.Menus[name]
+	if !ok {
+		return errors.New(fmt.Sprintf("The named menu '%s' doesn't exists.", name))
+	}
+	mm.push(m, TransitionForward)
+	return nil
+}
+
+func (mm *MenuManager) push(m *Menu, via Transition) {
+	if current := mm.Current(); current != nil {
+		current.hide(via)
+	}
+	mm.history = append(mm.history, m)
+	m.show(via)
+}
+
+// Pop hides the current menu and shows the one before it.
+// Popping the last menu in the history leaves no menu visible.
+func (mm *MenuManager) Pop() {
+	current := mm.Current()
+	if current == nil {
+		return
+	}
+	mm.history = mm.history[:len(mm.history)-1]
+	current.hide(TransitionBack)
+	if previous := mm.Current(); previous != nil {
+		previous.show(TransitionBack)
+	}
+}
+
+// Replace swaps the current menu for the named menu without growing the history
+func (mm *MenuManager) Replace(name string) error {
+	m, ok := mm.Menus[name]
+	if !ok {
+		return errors.New(fmt.Sprintf("The named menu '%s' doesn't exists.", name))
+	}
+	if current := mm.Current(); current != nil {
+		mm.history = mm.history[:len(mm.history)-1]
+		current.hide(TransitionReplace)
+	}
+	mm.history = append(mm.history, m)
+	m.show(TransitionReplace)
+	return nil
+}
+
+// makeCurrent restarts the history from a menu that was displayed without going through the stack
+func (mm *MenuManager) makeCurrent(m *Menu) {
+	if mm.Current() != m {
+		mm.history = []*Menu{m}
+	}
+}
+
+// IsEditing reports whether a textbox in a visible menu is currently accepting keyboard input
+func (mm *MenuManager) IsEditing() bool {
+	for _, menu := range append(mm.sortedMenus(), mm.dialogs...) {
+		if !menu.IsVisible {
+			continue
+		}
+		for _, textbox := range menu.TextBoxes {
+			if textbox.IsEdit {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (mm *MenuManager) Toggle(name string) error {
 	m, ok := mm.Menus[name]
 	if !ok {
@@ -152,7 +437,7 @@ func (mm *MenuManager) SetText(name string, index int, text string) error {
 	}
 	for i, l := range m.Labels {
 		if i == index {
-			l.Text.SetString(text)
+			l.SetString(text)
 		}
 	}
 	return nil
@@ -160,7 +445,8 @@ func (mm *MenuManager) SetText(name string, index int, text string) error {
 
 // NewMenuManager handles a tree of menus that interact with one another
 func NewMenuManager(font *v41.Font, startKey glfw.Key, startMenu string) *MenuManager {
-	mm := &MenuManager{Font: font, StartKey: startKey, StartMenu: startMenu}
+	mm := &MenuManager{Font: font, StartKey: startKey, StartMenu: startMenu, DimColor: mgl32.Vec4{0, 0, 0, 0.6}}
 	mm.Menus = make(map[string]*Menu)
+	mm.Notifications = newNotifications(mm)
 	return mm
 }
@@ -0,0 +1,161 @@
+package glmenu
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"testing"
+)
+
+// newTestManager builds menus that are never rendered so no opengl context is required
+func newTestManager(names ...string) *MenuManager {
+	mm := &MenuManager{Menus: make(map[string]*Menu)}
+	for _, name := range names {
+		mm.Menus[name] = &Menu{Name: name, MenuManager: mm, IsModal: true}
+	}
+	return mm
+}
+
+func TestMenuManagerHistory(t *testing.T) {
+	mm := newTestManager("main", "option", "video")
+
+	var shown, hidden []Transition
+	mm.Menus["main"].OnShowVia = func(via Transition) { shown = append(shown, via) }
+	mm.Menus["main"].OnHide = func(via Transition) { hidden = append(hidden, via) }
+
+	mm.Show("main")
+	if err := mm.Push("option"); err != nil {
+		t.Fatal(err)
+	}
+	if mm.Menus["main"].IsVisible || !mm.Menus["option"].IsVisible {
+		t.Error("push should hide the previous menu")
+	}
+	if err := mm.Replace("video"); err != nil {
+		t.Fatal(err)
+	}
+	if mm.Current() != mm.Menus["video"] || mm.Menus["option"].IsVisible {
+		t.Error("replace should swap the current menu", mm.Current().Name)
+	}
+	mm.Pop()
+	if mm.Current() != mm.Menus["main"] || !mm.Menus["main"].IsVisible {
+		t.Error("pop should return to main")
+	}
+	mm.Pop()
+	if mm.Current() != nil || mm.Menus["main"].IsVisible {
+		t.Error("popping the root should close the menus")
+	}
+	if err := mm.Push("missing"); err == nil {
+		t.Error("expected an error for an unknown menu")
+	}
+
+	expectShown := []Transition{TransitionNone, TransitionBack}
+	expectHidden := []Transition{TransitionForward, TransitionBack}
+	if len(shown) != len(expectShown) || shown[0] != expectShown[0] || shown[1] != expectShown[1] {
+		t.Error("shown", shown)
+	}
+	if len(hidden) != len(expectHidden) || hidden[0] != expectHidden[0] || hidden[1] != expectHidden[1] {
+		t.Error("hidden", hidden)
+	}
+}
+
+func TestMenuManagerLayers(t *testing.T) {
+	mm := newTestManager("hud", "main", "dialog")
+	mm.Menus["hud"].Layer = -1
+	mm.Menus["hud"].IsModal = false
+
+	mm.Menus["main"].Show()
+	mm.Menus["dialog"].Show()
+	mm.Menus["hud"].Show()
+
+	order := ""
+	for _, menu := range mm.Visible() {
+		order += menu.Name + " "
+	}
+	if order != "hud main dialog " {
+		t.Error("unexpected draw order", order)
+	}
+	if mm.keyTarget() != mm.Menus["dialog"] {
+		t.Error("keys should go to the topmost modal menu")
+	}
+
+	mm.Menus["dialog"].Hide()
+	if mm.keyTarget() != mm.Menus["main"] {
+		t.Error("keys should fall back to main", mm.keyTarget().Name)
+	}
+	mm.Menus["main"].Hide()
+	if mm.keyTarget() != mm.Menus["hud"] || len(mm.Visible()) != 1 {
+		t.Error("only the hud should remain")
+	}
+	if mm.IsModal() {
+		t.Error("the hud alone should not pause the game")
+	}
+}
+
+func TestDialogs(t *testing.T) {
+	mm := NewMenuManager(nil, glfw.KeyUnknown, "main")
+	main := &Menu{Name: "main", MenuManager: mm, IsModal: true}
+	mm.Menus["main"] = main
+
+	dialog := &Menu{Name: "dialog-1"}
+	mm.addDialog(dialog)
+	dialog.Show()
+	if len(mm.Menus) != 1 || mm.Menus["main"] != main {
+		t.Error("dialogs should not be added to Menus", mm.Menus)
+	}
+	if mm.DimColor.W() == 0 || dialog.DimColor != mm.DimColor {
+		t.Error("dialogs should dim the menus beneath them by default", dialog.DimColor)
+	}
+	if mm.keyTarget() != dialog {
+		t.Error("keys should go to the dialog")
+	}
+	mm.KeyRelease(glfw.KeyEscape, false)
+	if dialog.IsVisible || len(mm.dialogs) != 0 || len(mm.released) != 1 || mm.released[0] != dialog {
+		t.Error("escape should close the dialog and queue its release", dialog.IsVisible, len(mm.dialogs), len(mm.released))
+	}
+}
+
+func TestMenuManagerHotkeys(t *testing.T) {
+	mm := newTestManager("main", "option", "map")
+	mm.StartMenu = "main"
+	mm.StartKey = glfw.KeyM
+	mm.Menus["map"].ShowOnKey = glfw.KeyF1
+
+	mm.KeyRelease(glfw.KeyM, false)
+	if !mm.Menus["main"].IsVisible {
+		t.Fatal("start key should open the start menu")
+	}
+	mm.Push("option")
+	mm.KeyRelease(glfw.KeyM, false)
+	if !mm.Menus["main"].IsVisible || mm.Menus["option"].IsVisible {
+		t.Error("start key should back out of the submenu")
+	}
+	mm.KeyRelease(glfw.KeyM, false)
+	if mm.IsVisible() {
+		t.Error("start key should close the start menu")
+	}
+
+	mm.Menus["option"].Show()
+	mm.KeyRelease(glfw.KeyM, false)
+	if mm.Menus["option"].IsVisible {
+		t.Error("start key should close a menu shown without history")
+	}
+
+	mm.KeyRelease(glfw.KeyF1, false)
+	if !mm.Menus["map"].IsVisible {
+		t.Error("show on key should open its menu")
+	}
+	mm.DisableHotkeys = true
+	mm.KeyRelease(glfw.KeyF1, false)
+	if !mm.Menus["map"].IsVisible {
+		t.Error("hotkeys are disabled")
+	}
+	mm.DisableHotkeys = false
+	mm.KeyRelease(glfw.KeyF1, false)
+	if mm.Menus["map"].IsVisible {
+		t.Error("show on key should close its menu")
+	}
+
+	mm.Menus["map"].ShowOnKey = glfw.KeyTab
+	mm.KeyRelease(glfw.KeyTab, false)
+	if mm.Menus["map"].IsVisible {
+		t.Error("tab is kept for focus traversal")
+	}
+}
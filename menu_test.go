@@ -0,0 +1,130 @@
+package glmenu
+
+import (
+	"github.com/4ydx/gltext"
+	"github.com/4ydx/gltext/v4.1"
+	"testing"
+	"time"
+)
+
+// newTestLabel builds a label on a font without glyphs.  It still needs an opengl context to set its text.
+func newTestLabel(menu *Menu, text string, config LabelConfig) *Label {
+	label := &Label{Menu: menu, Text: &v41.Text{Font: newTestFont()}, Config: config}
+	label.SetString(text)
+	return label
+}
+
+func newTestTextBox(menu *Menu, text string) *TextBox {
+	f := newTestFont()
+	textbox := &TextBox{Menu: menu, Text: &v41.Text{Font: f}, Cursor: &v41.Text{Font: f}, Placeholder: &v41.Text{Font: f}}
+	textbox.SetString(text)
+	return textbox
+}
+
+func newTestFont() *v41.Font {
+	f := &v41.Font{}
+	f.Config = &gltext.FontConfig{}
+	return f
+}
+
+func TestMenuMove(t *testing.T) {
+	menu := &Menu{}
+	a, b, c := &Label{}, &TextBox{}, &Label{}
+	menu.Formatable = []Formatable{a, b, c}
+	menu.sortElements()
+
+	menu.Move(c, 0)
+	if menu.IndexOf(c) != 0 || menu.IndexOf(a) != 1 || menu.IndexOf(b) != 2 {
+		t.Error("unexpected order", menu.Formatable)
+	}
+	if len(menu.Labels) != 2 || menu.Labels[0] != c || menu.Labels[1] != a {
+		t.Error("labels should follow the new order")
+	}
+
+	menu.Move(c, 10)
+	if menu.IndexOf(c) != 2 {
+		t.Error("moving beyond the end places the element last", menu.IndexOf(c))
+	}
+	if menu.IndexOf(&Label{}) != -1 {
+		t.Error("unknown elements have no index")
+	}
+}
+
+func TestMenuRemove(t *testing.T) {
+	openGLContext()
+
+	menu := &Menu{}
+	a := newTestLabel(menu, "a", LabelConfig{Action: CUSTOM})
+	b := newTestLabel(menu, "b", LabelConfig{Action: CUSTOM})
+	c := newTestLabel(menu, "c", LabelConfig{Action: CUSTOM})
+	menu.Formatable = []Formatable{a, b, c}
+	menu.sortElements()
+	blurred := 0
+	menu.OnBlur = func(f Formatable) { blurred++ }
+
+	labels := menu.Labels
+	menu.Focus(b)
+	menu.Remove(b)
+	if menu.Focused() != nil || b.focused || blurred != 1 {
+		t.Error("removing the focused element should blur it", menu.Focused(), blurred)
+	}
+	if len(labels) != 3 || labels[0] != a || labels[1] != b || labels[2] != c {
+		t.Error("lists being iterated while an element is removed should not change", labels)
+	}
+	if len(menu.Labels) != 2 || menu.Labels[0] != a || menu.Labels[1] != c {
+		t.Error("unexpected labels", menu.Labels)
+	}
+
+	menu.Focus(c)
+	menu.Clear()
+	if menu.Focused() != nil || len(menu.Labels) != 0 || blurred != 2 {
+		t.Error("clearing should blur and remove everything", menu.Focused(), len(menu.Labels), blurred)
+	}
+}
+
+func TestLabelProvider(t *testing.T) {
+	openGLContext()
+
+	menu := &Menu{}
+	label := newTestLabel(menu, "", LabelConfig{})
+	menu.Labels = []*Label{label}
+	fps := 60
+	calls := 0
+	label.SetTemplate(time.Second, "FPS: %d", func() interface{} {
+		calls++
+		return fps
+	})
+
+	start := time.Now()
+	menu.refreshProviders(start)
+	if label.String() != "FPS: 60" {
+		t.Fatal(label.String())
+	}
+	fps = 59
+	menu.refreshProviders(start.Add(time.Second / 2))
+	if calls != 1 || label.String() != "FPS: 60" {
+		t.Error("the provider should wait for its interval", calls)
+	}
+	if label.provide(start.Add(time.Second)) || label.String() != "FPS: 59" {
+		t.Error("an unchanged width should not need a relayout", label.String())
+	}
+	fps = 120
+	if !label.provide(start.Add(2 * time.Second)) {
+		t.Error("a wider text should need a relayout")
+	}
+}
+
+func TestLabelEnabled(t *testing.T) {
+	openGLContext()
+
+	label := newTestLabel(&Menu{}, "Options", LabelConfig{Action: GOTO_MENU})
+	label.IsHover = true
+	label.SetEnabled(false)
+	if label.IsEnabled() || !label.IsNoop() || label.IsHover {
+		t.Error("disabled labels should be skipped and lose their hover")
+	}
+	label.SetEnabled(true)
+	if !label.IsEnabled() || label.IsNoop() {
+		t.Error("enabled labels should be navigable again")
+	}
+}
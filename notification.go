@@ -0,0 +1,290 @@
+package glmenu
+
+import (
+	"errors"
+	"fmt"
+	"github.com/4ydx/gltext/v4.1"
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"time"
+)
+
+// Notifications displays short lived messages, toasts, stacked against one corner of the window.
+// Toasts are drawn by MenuManager.Draw whether or not any menu is visible and never receive input.
+type Notifications struct {
+	MenuManager *MenuManager
+
+	Defaults       MenuDefaults // when unset toasts borrow the StartMenu defaults
+	ScreenPosition ScreenPosition
+	ScreenOffset   Offset
+	Duration       time.Duration // how long a toast is fully visible
+	Fade           time.Duration // how long fading in and fading out each take
+	MaxVisible     int           // further toasts wait in the queue
+	Spacing        float32       // logical pixels between stacked toasts
+
+	queue   []string
+	active  []*toast
+	failure string // the last error met while showing a queued toast, reported once
+
+	// every toast is drawn with the host menu's shader and a shared unit quad
+	host                      *Menu
+	quadVao, quadVbo, quadEbo uint32
+}
+
+type toast struct {
+	text   *v41.Text
+	center mgl32.Vec2 // framebuffer pixels from the center of the window
+	size   mgl32.Vec2 // framebuffer pixels, excluding the border
+	start  time.Time
+}
+
+func newNotifications(mm *MenuManager) *Notifications {
+	return &Notifications{
+		MenuManager:    mm,
+		ScreenPosition: ScreenTopRight,
+		ScreenOffset:   DefaultOffset(ScreenTopRight),
+		Duration:       3 * time.Second,
+		Fade:           250 * time.Millisecond,
+		MaxVisible:     3,
+		Spacing:        5,
+	}
+}
+
+// Notify queues a toast.  It appears as soon as there is room for it.
+func (mm *MenuManager) Notify(message string, argv ...interface{}) {
+	if len(argv) > 0 {
+		message = fmt.Sprintf(message, argv...)
+	}
+	if mm.Notifications == nil {
+		mm.Notifications = newNotifications(mm)
+	}
+	mm.Notifications.queue = append(mm.Notifications.queue, message)
+}
+
+// SetPosition anchors the toast stack.  Toasts grow away from the anchored edge.
+func (n *Notifications) SetPosition(anchor ScreenPosition, offset Offset) {
+	n.ScreenPosition = anchor
+	n.ScreenOffset = offset
+	n.arrange()
+}
+
+// Clear removes every queued and visible toast
+func (n *Notifications) Clear() {
+	n.queue = nil
+	for _, t := range n.active {
+		t.text.Release()
+	}
+	n.active = nil
+}
+
+// Release clears the toasts and frees the shared GL objects
+func (n *Notifications) Release() {
+	n.Clear()
+	if n.host == nil {
+		return
+	}
+	gl.DeleteBuffers(1, &n.quadVbo)
+	gl.DeleteBuffers(1, &n.quadEbo)
+	gl.DeleteVertexArrays(1, &n.quadVao)
+	n.host.Release()
+	n.host = nil
+}
+
+// Draw renders every visible toast.  Backgrounds and borders fade through their alpha while the text,
+// which has no alpha, is blended towards the background color.
+func (n *Notifications) Draw() {
+	n.update(time.Now())
+	for _, t := range n.active {
+		if alpha := fadeAlpha(time.Since(t.start), n.Duration, n.Fade); alpha > 0 {
+			n.drawToast(t, alpha)
+		}
+	}
+}
+
+// drawToast draws the border, background and text of a toast in the same way that a menu draws its own
+func (n *Notifications) drawToast(t *toast, alpha float32) {
+	host := n.host
+	style := host.style(StyleNormal)
+	border := host.Defaults.Border.Mul(host.ContentScale)
+	position := mgl32.Vec2{t.center.X() / (host.FramebufferWidth / 2), t.center.Y() / (host.FramebufferHeight / 2)}
+
+	gl.UseProgram(host.program)
+	gl.UniformMatrix4fv(host.orthographicUniform, 1, false, &host.Font.OrthographicMatrix[0])
+	gl.Uniform2fv(host.finalPositionUniform, 1, &position[0])
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.BindVertexArray(n.quadVao)
+	for i, color := range []mgl32.Vec4{style.BorderColor, style.BackgroundColor} {
+		scale := mgl32.Scale3D(t.size.X(), t.size.Y(), 1)
+		if i == 0 {
+			scale = mgl32.Scale3D(t.size.X()+2*border.X(), t.size.Y()+2*border.Y(), 1)
+		}
+		color[3] *= alpha
+		gl.UniformMatrix4fv(host.scaleUniform, 1, false, &scale[0])
+		gl.Uniform4fv(host.backgroundUniform, 1, &color[0])
+		gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, nil)
+	}
+	gl.BindVertexArray(0)
+	gl.Disable(gl.BLEND)
+
+	t.text.SetColor(fadeText(style.TextColor, style.BackgroundColor, alpha))
+	t.text.Draw()
+}
+
+// fadeText moves the text color towards the background color as alpha drops
+func fadeText(text mgl32.Vec3, background mgl32.Vec4, alpha float32) mgl32.Vec3 {
+	var color mgl32.Vec3
+	for i := range color {
+		color[i] = background[i] + (text[i]-background[i])*alpha
+	}
+	return color
+}
+
+// update retires expired toasts and promotes queued messages once space is available
+func (n *Notifications) update(now time.Time) {
+	changed := false
+	lifetime := n.Duration + 2*n.Fade
+	for i := 0; i < len(n.active); i++ {
+		if now.Sub(n.active[i].start) >= lifetime {
+			n.active[i].text.Release()
+			n.active = append(n.active[:i], n.active[i+1:]...)
+			i--
+			changed = true
+		}
+	}
+	for len(n.queue) > 0 && (n.MaxVisible <= 0 || len(n.active) < n.MaxVisible) {
+		// toasts that can't be shown yet, ie before the first menu exists, stay queued
+		t, err := n.newToast(n.queue[0])
+		if err != nil {
+			if err.Error() != n.failure {
+				n.failure = err.Error()
+				MenuDebug(err.Error())
+			}
+			break
+		}
+		n.failure = ""
+		n.queue = n.queue[1:]
+		t.start = now
+		n.active = append(n.active, t)
+		changed = true
+	}
+	if changed {
+		n.arrange()
+	}
+}
+
+func (n *Notifications) newToast(message string) (*toast, error) {
+	if err := n.prepare(); err != nil {
+		return nil, err
+	}
+	t := &toast{text: v41.NewText(n.host.Font, 1.0, 1.0)}
+	t.text.SetString(n.host.display(DirectionAuto, message))
+	return t, nil
+}
+
+// prepare creates the host menu and the shared quad when the first toast is shown
+func (n *Notifications) prepare() error {
+	if n.host != nil {
+		return nil
+	}
+	mm := n.MenuManager
+	if mm.window == nil {
+		return errors.New("A menu must be created before notifications can be displayed")
+	}
+	defaults := n.Defaults
+	if defaults == (MenuDefaults{}) {
+		defaults = mm.fallbackDefaults()
+	}
+	host, err := NewMenu(mm.window, "toasts", mm.Font, defaults, n.ScreenPosition)
+	if err != nil {
+		return err
+	}
+	n.host = host
+	n.quadVao, n.quadVbo, n.quadEbo = newUnitQuad(host.position)
+	return nil
+}
+
+// newUnitQuad uploads a unit quad centered on the origin for the menu's shader, which stretches it with the scale matrix
+func newUnitQuad(position uint32) (vao, vbo, ebo uint32) {
+	vboData := []float32{-0.5, -0.5, 0.5, -0.5, 0.5, 0.5, -0.5, 0.5}
+	eboData := []int32{0, 1, 2, 0, 2, 3}
+
+	gl.GenVertexArrays(1, &vao)
+	gl.GenBuffers(1, &vbo)
+	gl.GenBuffers(1, &ebo)
+
+	gl.BindVertexArray(vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
+	gl.EnableVertexAttribArray(position)
+	gl.VertexAttribPointer(
+		position,
+		2,
+		gl.FLOAT,
+		false,
+		0,
+		gl.PtrOffset(0),
+	)
+	gl.BufferData(gl.ARRAY_BUFFER, 4*len(vboData), gl.Ptr(vboData), gl.STATIC_DRAW)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, ebo)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, 4*len(eboData), gl.Ptr(eboData), gl.STATIC_DRAW)
+	gl.BindVertexArray(0)
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
+	return
+}
+
+// arrange stacks the toasts away from the anchor, the oldest being closest to it
+func (n *Notifications) arrange() {
+	if n.host == nil {
+		return
+	}
+	host := n.host
+	scale := host.ContentScale
+	padding := host.padding().Mul(scale)
+	border := host.Defaults.Border.Mul(scale)
+	minWidth, minHeight := host.minimumSize()
+	direction := float32(-1)
+	switch n.ScreenPosition {
+	case ScreenLowerLeft, ScreenLowerCenter, ScreenLowerRight:
+		direction = 1
+	}
+	shift := float32(0)
+	for _, t := range n.active {
+		t.size = mgl32.Vec2{t.text.Width() + padding.X()*2, t.text.Height() + padding.Y()*2}
+		if t.size[0] < minWidth {
+			t.size[0] = minWidth
+		}
+		if t.size[1] < minHeight {
+			t.size[1] = minHeight
+		}
+		offset := n.ScreenOffset
+		offset.Y = Px(offset.Y.pixels(host.FramebufferHeight, scale)/scale + shift)
+		t.center = anchorPosition(n.ScreenPosition, offset, host.FramebufferWidth, host.FramebufferHeight, t.size.X(), t.size.Y(), scale)
+		t.text.SetPosition(t.center)
+		shift += direction * ((t.size.Y()+2*border.Y())/scale + n.Spacing)
+	}
+}
+
+// Resize lays out the toasts again for the new framebuffer dimensions
+func (n *Notifications) Resize(width, height float32) {
+	if n.host == nil {
+		return
+	}
+	n.host.Resize(width, height)
+	n.arrange()
+}
+
+// fadeAlpha ramps from 0 to 1 over fade, holds for duration and then ramps back down to 0
+func fadeAlpha(elapsed, duration, fade time.Duration) float32 {
+	switch {
+	case elapsed < 0 || elapsed >= duration+2*fade:
+		return 0
+	case elapsed < fade:
+		return float32(elapsed) / float32(fade)
+	case elapsed < fade+duration:
+		return 1
+	default:
+		return float32(duration+2*fade-elapsed) / float32(fade)
+	}
+}
@@ -0,0 +1,60 @@
+package glmenu
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"testing"
+	"time"
+)
+
+func TestFadeAlpha(t *testing.T) {
+	duration, fade := 2*time.Second, time.Second
+	tests := []struct {
+		elapsed time.Duration
+		alpha   float32
+	}{
+		{-time.Second, 0},
+		{0, 0},
+		{500 * time.Millisecond, 0.5},
+		{time.Second, 1},
+		{3 * time.Second, 1},
+		{3500 * time.Millisecond, 0.5},
+		{4 * time.Second, 0},
+	}
+	for _, test := range tests {
+		if alpha := fadeAlpha(test.elapsed, duration, fade); alpha != test.alpha {
+			t.Error(test.elapsed, alpha, test.alpha)
+		}
+	}
+}
+
+func TestNotificationsWithoutConstructor(t *testing.T) {
+	mm := &MenuManager{}
+	mm.Draw()
+	mm.Resize(640, 480)
+	mm.Release()
+
+	mm.Notify("Saved %d files", 2)
+	if mm.Notifications == nil || len(mm.Notifications.queue) != 1 || mm.Notifications.queue[0] != "Saved 2 files" {
+		t.Error("notify should create the notifications on demand")
+	}
+}
+
+func TestFadeText(t *testing.T) {
+	text, background := mgl32.Vec3{1, 1, 1}, mgl32.Vec4{0, 0.5, 1, 0.8}
+	if color := fadeText(text, background, 0.5); color != (mgl32.Vec3{0.5, 0.75, 1}) {
+		t.Error("text should be blended halfway into the background", color)
+	}
+	if color := fadeText(text, background, 1); color != text {
+		t.Error(color)
+	}
+}
+
+func TestNotificationsWaitForAMenu(t *testing.T) {
+	mm := &MenuManager{}
+	mm.Notify("Saved")
+	mm.Notifications.update(time.Now())
+	mm.Notifications.update(time.Now())
+	if len(mm.Notifications.queue) != 1 || len(mm.Notifications.active) != 0 || mm.Notifications.failure == "" {
+		t.Error("toasts should stay queued until they can be shown")
+	}
+}
@@ -0,0 +1,305 @@
+package glmenu
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// persistent is implemented by the elements whose value can be saved, namely textboxes, checkboxes,
+// sliders and selectors that have been given an ID
+type persistent interface {
+	id() string
+	savedValue() interface{}
+	restore(value interface{}) error
+}
+
+// ValueFile saves the values of a menu's widgets between runs, keyed by each widget's ID.
+// Files ending in .ini are written as INI, anything else as JSON, ie
+//
+//	{"version": 2, "values": {"volume": 40, "fullscreen": true, "difficulty": "Hard"}}
+//
+// Widgets missing from the file keep the value they were created with and values of widgets that no longer
+// exist are ignored.  Values saved by an older Version are passed to Migrate before they are restored.
+type ValueFile struct {
+	Path    string
+	Version int
+	Migrate func(from int, values map[string]interface{})
+}
+
+type valueFile struct {
+	Version int                    `json:"version"`
+	Values  map[string]interface{} `json:"values"`
+}
+
+// Values returns the value of each widget with an ID
+func (menu *Menu) Values() map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, p := range menu.persistents() {
+		values[p.id()] = p.savedValue()
+	}
+	return values
+}
+
+// SetValues restores the widgets whose ID is found in values.  Values that do not suit their widget are
+// skipped, leaving the widget as it was, and reported in the returned error.
+func (menu *Menu) SetValues(values map[string]interface{}) error {
+	problems := make([]string, 0)
+	for _, p := range menu.persistents() {
+		value, ok := values[p.id()]
+		if !ok {
+			continue
+		}
+		if err := p.restore(value); err != nil {
+			problems = append(problems, fmt.Sprintf("The value of '%s': %s", p.id(), err.Error()))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+func (menu *Menu) persistents() []persistent {
+	found := make([]persistent, 0)
+	for _, f := range menu.Formatable {
+		var p persistent
+		switch element := f.(type) {
+		case *Label:
+			p = element.widget
+		case *TextBox:
+			p = element
+		}
+		if p != nil && p.id() != "" {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// Save writes the menu's values, replacing the file in a single step so that a crash can't leave half a file
+func (vf ValueFile) Save(menu *Menu) error {
+	file := valueFile{Version: vf.Version, Values: menu.Values()}
+	var data []byte
+	if vf.isINI() {
+		data = file.ini()
+	} else {
+		var err error
+		if data, err = json.MarshalIndent(file, "", "\t"); err != nil {
+			return err
+		}
+	}
+	// the temporary file is private to its owner, so it takes the mode of the file it replaces
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(vf.Path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(vf.Path), filepath.Base(vf.Path))
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Chmod(mode)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), vf.Path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// Load restores the menu's values.  A file that doesn't exist yet is not an error.
+// Call it before Finalize so that the menu is laid out once.  The restored values of a SettingsMenu
+// are applied to its struct, calling OnApply, since showing the menu reloads the widgets from the struct.
+func (vf ValueFile) Load(menu *Menu) error {
+	data, err := ioutil.ReadFile(vf.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var file valueFile
+	if vf.isINI() {
+		file, err = parseINI(vf.Path, data)
+	} else {
+		file, err = parseValues(vf.Path, data)
+	}
+	if err != nil {
+		return err
+	}
+	if file.Values == nil {
+		file.Values = make(map[string]interface{})
+	}
+	if file.Version > vf.Version {
+		MenuDebug(fmt.Sprintf("%s was saved by a newer version (%d > %d)", vf.Path, file.Version, vf.Version))
+	}
+	if file.Version < vf.Version && vf.Migrate != nil {
+		vf.Migrate(file.Version, file.Values)
+	}
+	err = menu.SetValues(file.Values)
+	if menu.settings != nil {
+		menu.settings.Apply()
+	}
+	return err
+}
+
+func (vf ValueFile) isINI() bool {
+	return strings.EqualFold(filepath.Ext(vf.Path), ".ini")
+}
+
+func parseValues(name string, data []byte) (valueFile, error) {
+	file := valueFile{}
+	err := decodeJSON(name, data, &file)
+	return file, err
+}
+
+// ini writes one key = value line per widget, sorted by key.  Strings that would not survive
+// the trimming done when parsing are quoted.
+func (file valueFile) ini() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "version = %d\n\n", file.Version)
+	keys := make([]string, 0, len(file.Values))
+	for key := range file.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		str := fmt.Sprint(file.Values[key])
+		if _, ok := file.Values[key].(string); ok && (strings.TrimSpace(str) != str || strings.HasPrefix(str, `"`)) {
+			str = strconv.Quote(str)
+		}
+		fmt.Fprintf(&buf, "%s = %s\n", key, str)
+	}
+	return buf.Bytes()
+}
+
+// parseINI reads key = value lines, skipping blank lines and comments starting with ; or #.
+// Values are kept as strings and converted by the widgets restoring them.
+func parseINI(name string, data []byte) (valueFile, error) {
+	file := valueFile{Values: make(map[string]interface{})}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == ';' || text[0] == '#' {
+			continue
+		}
+		fail := func(message string) (valueFile, error) {
+			return file, &LoadError{File: name, Line: line, Column: 1, Err: errors.New(message)}
+		}
+		i := strings.Index(text, "=")
+		if i < 0 {
+			return fail(fmt.Sprintf("expected key = value rather than '%s'", text))
+		}
+		key, value := strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:])
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return fail(fmt.Sprintf("the value of '%s' is badly quoted", key))
+			}
+			value = unquoted
+		}
+		if key == "version" {
+			version, err := strconv.Atoi(value)
+			if err != nil {
+				return fail(fmt.Sprintf("the version '%s' is not a number", value))
+			}
+			file.Version = version
+			continue
+		}
+		file.Values[key] = value
+	}
+	return file, scanner.Err()
+}
+
+func (textbox *TextBox) id() string {
+	return textbox.ID
+}
+
+func (textbox *TextBox) savedValue() interface{} {
+	return textbox.Value()
+}
+
+func (textbox *TextBox) restore(value interface{}) error {
+	converted, err := convertTo(value, reflect.TypeOf(""))
+	if err != nil {
+		return err
+	}
+	textbox.SetString("%s", converted.String())
+	textbox.binding.push(textbox.Value())
+	return nil
+}
+
+func (checkbox *Checkbox) id() string {
+	return checkbox.ID
+}
+
+func (checkbox *Checkbox) savedValue() interface{} {
+	return checkbox.Value
+}
+
+func (checkbox *Checkbox) restore(value interface{}) error {
+	converted, err := convertTo(value, reflect.TypeOf(false))
+	if err != nil {
+		return err
+	}
+	checkbox.Set(converted.Bool())
+	checkbox.Label.binding.push(checkbox.Value)
+	return nil
+}
+
+func (slider *Slider) id() string {
+	return slider.ID
+}
+
+func (slider *Slider) savedValue() interface{} {
+	return slider.Value
+}
+
+func (slider *Slider) restore(value interface{}) error {
+	converted, err := convertTo(value, reflect.TypeOf(float64(0)))
+	if err != nil {
+		return err
+	}
+	slider.Set(converted.Float())
+	slider.Label.binding.push(slider.Value)
+	return nil
+}
+
+func (selector *Selector) id() string {
+	return selector.ID
+}
+
+// savedValue is the text of the option so that saved values survive options being reordered
+func (selector *Selector) savedValue() interface{} {
+	return selector.Selected()
+}
+
+func (selector *Selector) restore(value interface{}) error {
+	str, ok := value.(string)
+	if !ok {
+		return errors.New(fmt.Sprintf("expected one of the options rather than %v", value))
+	}
+	for i, option := range selector.Options {
+		if option == str {
+			selector.Set(i)
+			selector.push()
+			return nil
+		}
+	}
+	return errors.New(fmt.Sprintf("'%s' is not one of the options", str))
+}
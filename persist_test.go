@@ -0,0 +1,155 @@
+package glmenu
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func testValueMenu() (*Menu, *Checkbox, *Slider, *Selector) {
+	menu := &Menu{}
+	label := func() *Label {
+		label := newTestLabel(menu, "", LabelConfig{})
+		menu.Labels = append(menu.Labels, label)
+		menu.Formatable = append(menu.Formatable, label)
+		return label
+	}
+	checkbox := &Checkbox{ID: "sound", Label: label(), Text: "Sound", Value: true}
+	slider := &Slider{ID: "volume", Label: label(), Text: "Volume", Value: 50, Max: 100, Step: 10}
+	selector := &Selector{ID: "difficulty", Label: label(), Text: "Difficulty", Options: []string{"Easy", "Normal", "Hard"}, Index: 1}
+	unsaved := &Checkbox{Label: label(), Text: "Unsaved"}
+	for _, widget := range []persistent{checkbox, slider, selector, unsaved} {
+		switch w := widget.(type) {
+		case *Checkbox:
+			w.Label.widget = w
+		case *Slider:
+			w.Label.widget = w
+		case *Selector:
+			w.Label.widget = w
+		}
+	}
+	return menu, checkbox, slider, selector
+}
+
+func TestValueFile(t *testing.T) {
+	openGLContext()
+
+	dir, err := ioutil.TempDir("", "glmenu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"settings.json", "settings.ini"} {
+		vf := ValueFile{Path: filepath.Join(dir, name), Version: 1}
+		menu, checkbox, slider, selector := testValueMenu()
+		if err := vf.Load(menu); err != nil {
+			t.Fatal("a missing file should keep the defaults", err)
+		}
+		checkbox.Value, slider.Value, selector.Index = false, 70, 2
+		if err := vf.Save(menu); err != nil {
+			t.Fatal(err)
+		}
+		if mode := fileMode(t, vf.Path); mode != 0644 {
+			t.Error("new files should be readable by everyone", mode)
+		}
+		os.Chmod(vf.Path, 0600)
+		if err := vf.Save(menu); err != nil {
+			t.Fatal(err)
+		}
+		if mode := fileMode(t, vf.Path); mode != 0600 {
+			t.Error("saving should keep the mode of the existing file", mode)
+		}
+
+		menu, checkbox, slider, selector = testValueMenu()
+		if err := vf.Load(menu); err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]interface{}{"sound": false, "volume": float64(70), "difficulty": "Hard"}
+		if !reflect.DeepEqual(menu.Values(), expected) {
+			t.Errorf("%s: expected %v, got %v", name, expected, menu.Values())
+		}
+	}
+}
+
+func fileMode(t *testing.T, path string) os.FileMode {
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Mode().Perm()
+}
+
+func TestValueFileMigrate(t *testing.T) {
+	openGLContext()
+
+	dir, err := ioutil.TempDir("", "glmenu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "settings.ini")
+	data := "; old settings\nversion = 1\nloudness = 20\nremoved = x\ndifficulty = Impossible\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	migrated := 0
+	vf := ValueFile{Path: path, Version: 2, Migrate: func(from int, values map[string]interface{}) {
+		migrated = from
+		values["volume"] = values["loudness"]
+	}}
+	menu, checkbox, slider, selector := testValueMenu()
+	err = vf.Load(menu)
+	if migrated != 1 || slider.Value != 20 {
+		t.Error("values should be migrated", migrated, slider.Value)
+	}
+	if !checkbox.Value || selector.Index != 1 {
+		t.Error("missing and unsuitable values should keep the defaults")
+	}
+	if err == nil || err.Error() != "The value of 'difficulty': 'Impossible' is not one of the options" {
+		t.Error(err)
+	}
+}
+
+func TestValueFileSettingsMenu(t *testing.T) {
+	openGLContext()
+
+	dir, err := ioutil.TempDir("", "glmenu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "settings.json")
+	if err := ioutil.WriteFile(path, []byte(`{"version": 1, "values": {"Volume": 70, "Fullscreen": false}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	menu := &Menu{}
+	label := func() *Label {
+		label := newTestLabel(menu, "", LabelConfig{})
+		menu.Labels = append(menu.Labels, label)
+		menu.Formatable = append(menu.Formatable, label)
+		return label
+	}
+	settings := &testSettings{Volume: 40, Fullscreen: true}
+	s := newSettingsMenu(menu, reflect.ValueOf(settings).Elem())
+	volume := &Slider{ID: "Volume", Label: label(), Text: "Volume", Min: 0, Max: 100, Step: 5}
+	volume.Label.widget = volume
+	fullscreen := &Checkbox{ID: "Fullscreen", Label: label(), Text: "Fullscreen"}
+	fullscreen.Label.widget = fullscreen
+	s.fields = []*settingField{{index: 0, slider: volume}, {index: 2, checkbox: fullscreen}}
+	s.Reset()
+
+	if err := (ValueFile{Path: path, Version: 1}).Load(menu); err != nil {
+		t.Fatal(err)
+	}
+	menu.Show()
+	if settings.Volume != 70 || settings.Fullscreen || volume.Value != 70 || fullscreen.Value {
+		t.Errorf("loaded values should survive showing the menu, got %+v", settings)
+	}
+}
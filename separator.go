@@ -0,0 +1,90 @@
+package glmenu
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Separator is a horizontal rule spanning the width of the menu.
+// It is drawn with the menu's own shader as a unit quad that is stretched by the scale matrix.
+type Separator struct {
+	Menu      *Menu
+	Thickness float32 // logical pixels
+	Padding   Padding
+	Color     mgl32.Vec4
+
+	Position      mgl32.Vec2
+	finalPosition mgl32.Vec2
+	scaleMatrix   mgl32.Mat4
+	vao           uint32
+	vbo           uint32
+	ebo           uint32
+}
+
+func (separator *Separator) load() {
+	separator.vao, separator.vbo, separator.ebo = newUnitQuad(separator.Menu.position)
+}
+
+func (separator *Separator) GetPosition() mgl32.Vec2 {
+	return separator.Position
+}
+
+// SetPosition is called once the menu width is known so the rule is stretched across the menu here
+func (separator *Separator) SetPosition(v mgl32.Vec2) {
+	menu := separator.Menu
+	separator.Position = v
+	separator.finalPosition[0] = v.X() / (menu.FramebufferWidth / 2)
+	separator.finalPosition[1] = v.Y() / (menu.FramebufferHeight / 2)
+
+	width := menu.Width - (menu.padding().X()+separator.Padding.X)*menu.ContentScale*2
+	separator.scaleMatrix = mgl32.Scale3D(width, separator.Height(), 1)
+}
+
+func (separator *Separator) GetPadding() Padding {
+	return separator.Padding
+}
+
+func (separator *Separator) Draw() {
+	menu := separator.Menu
+	gl.UniformMatrix4fv(menu.scaleUniform, 1, false, &separator.scaleMatrix[0])
+	gl.Uniform4fv(menu.backgroundUniform, 1, &separator.Color[0])
+	gl.Uniform2fv(menu.finalPositionUniform, 1, &separator.finalPosition[0])
+	gl.UniformMatrix4fv(menu.orthographicUniform, 1, false, &menu.Font.OrthographicMatrix[0])
+
+	gl.BindVertexArray(separator.vao)
+	gl.DrawElements(gl.TRIANGLES, 6, gl.UNSIGNED_INT, nil)
+	gl.BindVertexArray(0)
+}
+
+func (separator *Separator) Release() {
+	gl.DeleteBuffers(1, &separator.vbo)
+	gl.DeleteBuffers(1, &separator.ebo)
+	gl.DeleteVertexArrays(1, &separator.vao)
+}
+
+func (separator *Separator) Height() float32 {
+	return separator.Thickness * separator.Menu.ContentScale
+}
+
+// Width is zero so that separators never widen a menu.  They span whatever width the other elements require.
+func (separator *Separator) Width() float32 {
+	return 0
+}
+
+func (separator *Separator) Follow() bool {
+	return false
+}
+
+func (separator *Separator) NavigateTo() {}
+
+func (separator *Separator) NavigateAway() bool {
+	return false
+}
+
+func (separator *Separator) IsNoop() bool {
+	return true
+}
+
+func (separator *Separator) Type() FormatableType {
+	return FormatableSeparator
+}
@@ -0,0 +1,377 @@
+package glmenu
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SettingsMenu edits the tagged fields of a struct.  Widgets work on a copy of the values:
+// Apply writes them into the struct while Cancel, escape and showing the menu again reload them.
+// Changes the game makes to the struct while the menu is open are shown on the next Draw, replacing
+// any edit of that field.
+//
+// Fields are tagged with a label, an optional widget and options, ie
+//
+//	type GameSettings struct {
+//		Volume     int     `menu:"Volume,slider,min=0,max=100,step=5"`
+//		Gamma      float64 `menu:"Gamma,number,min=0.5,max=2,step=0.1,format=%.1f"`
+//		Fullscreen bool    `menu:"Fullscreen"`
+//		Name       string  `menu:"Name,textbox,width=200"`
+//		Difficulty int     `menu:"Difficulty,selector,options=Easy|Normal|Hard"`
+//	}
+//
+// Widgets default to checkbox for bools, number for numbers, textbox for strings and selector whenever
+// options are given.  Integer selectors store the index of the option, string selectors its text.
+type SettingsMenu struct {
+	Menu     *Menu
+	OnApply  func()
+	OnCancel func()
+
+	target reflect.Value
+	fields []*settingField
+}
+
+type settingField struct {
+	index  int
+	widget string
+
+	checkbox *Checkbox
+	slider   *Slider
+	selector *Selector
+	textbox  *TextBox
+}
+
+type settingTag struct {
+	label   string
+	widget  string
+	min     float64
+	max     float64
+	step    float64
+	format  string
+	width   float32
+	options []string
+}
+
+// NewSettingsMenu builds a menu for settings, which must be a pointer to a struct, followed by Apply and Cancel labels.
+// Apply and Cancel are translation keys, see Localizer.
+func (mm *MenuManager) NewSettingsMenu(window *glfw.Window, name string, defaults MenuDefaults, screenPosition ScreenPosition, settings interface{}) (*SettingsMenu, error) {
+	target := reflect.ValueOf(settings)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Struct {
+		return nil, errors.New(fmt.Sprintf("Settings must be a pointer to a struct, not %T", settings))
+	}
+	target = target.Elem()
+	tags := make(map[int]settingTag)
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Type().Field(i)
+		tag, ok := field.Tag.Lookup("menu")
+		if !ok || tag == "-" {
+			continue
+		}
+		parsed, err := parseSettingTag(field, tag)
+		if err != nil {
+			return nil, err
+		}
+		tags[i] = parsed
+	}
+
+	menu, err := mm.NewMenu(window, name, defaults, screenPosition)
+	if err != nil {
+		return nil, err
+	}
+	s := newSettingsMenu(menu, target)
+	for i := 0; i < target.NumField(); i++ {
+		tag, ok := tags[i]
+		if !ok {
+			continue
+		}
+		f := &settingField{index: i, widget: tag.widget}
+		switch tag.widget {
+		case "checkbox":
+			f.checkbox = menu.NewCheckbox(tag.label, false, LabelConfig{})
+		case "slider", "number":
+			f.slider = menu.NewSlider(tag.label, tag.min, tag.min, tag.max, tag.step, LabelConfig{})
+			f.slider.Format = tag.format
+			if tag.widget == "slider" {
+				f.slider.Bar = 10
+			}
+		case "selector":
+			f.selector = menu.NewSelector(tag.label, tag.options, 0, LabelConfig{})
+		case "textbox":
+			menu.NewLabel(tag.label, LabelConfig{Action: NOOP})
+			f.textbox = menu.NewTextBox("", tag.width, promptHeight, 1)
+		}
+		// field names identify the saved values, see ValueFile
+		id := target.Type().Field(i).Name
+		switch {
+		case f.checkbox != nil:
+			f.checkbox.ID = id
+		case f.slider != nil:
+			f.slider.ID = id
+		case f.selector != nil:
+			f.selector.ID = id
+		case f.textbox != nil:
+			f.textbox.ID = id
+		}
+		s.fields = append(s.fields, f)
+	}
+	s.bind()
+
+	// the English text doubles as the translation key so that a Localizer catalog can translate it
+	apply := menu.NewLabelKey("Apply", LabelConfig{Action: CUSTOM})
+	apply.onRelease = func(xPos, yPos float64, button MouseClick, inBox bool) {
+		if inBox {
+			s.Apply()
+		}
+	}
+	cancel := menu.NewLabelKey("Cancel", LabelConfig{Action: CUSTOM})
+	cancel.onRelease = func(xPos, yPos float64, button MouseClick, inBox bool) {
+		if inBox {
+			s.Cancel()
+		}
+	}
+	menu.OnCancel = s.Cancel
+	menu.EnterAdvances = true
+	return s, nil
+}
+
+// newSettingsMenu ties the menu to the struct it edits.  Showing the menu reloads the widgets from the struct.
+func newSettingsMenu(menu *Menu, target reflect.Value) *SettingsMenu {
+	s := &SettingsMenu{Menu: menu, target: target}
+	menu.settings = s
+	menu.OnShow = s.Reset
+	return s
+}
+
+func parseSettingTag(field reflect.StructField, tag string) (settingTag, error) {
+	parts := strings.Split(tag, ",")
+	parsed := settingTag{label: parts[0], step: 1, width: promptWidth}
+	if parsed.label == "" {
+		parsed.label = field.Name
+	}
+	fail := func(format string, argv ...interface{}) (settingTag, error) {
+		return parsed, errors.New(fmt.Sprintf("The setting %s: %s", field.Name, fmt.Sprintf(format, argv...)))
+	}
+	if field.PkgPath != "" {
+		return fail("only exported fields can be edited")
+	}
+	hasMin, hasMax := false, false
+	for _, part := range parts[1:] {
+		key, value := part, ""
+		if i := strings.Index(part, "="); i >= 0 {
+			key, value = part[:i], part[i+1:]
+		}
+		var err error
+		switch key {
+		case "checkbox", "slider", "number", "selector", "textbox":
+			parsed.widget = key
+		case "min":
+			parsed.min, err = strconv.ParseFloat(value, 64)
+			hasMin = true
+		case "max":
+			parsed.max, err = strconv.ParseFloat(value, 64)
+			hasMax = true
+		case "step":
+			parsed.step, err = strconv.ParseFloat(value, 64)
+		case "format":
+			parsed.format = value
+		case "width":
+			var width float64
+			width, err = strconv.ParseFloat(value, 32)
+			parsed.width = float32(width)
+		case "options":
+			parsed.options = strings.Split(value, "|")
+		default:
+			return fail("unknown option '%s'", part)
+		}
+		if err != nil {
+			return fail("'%s' is not a number", value)
+		}
+	}
+
+	kind := field.Type.Kind()
+	isInt := kind >= reflect.Int && kind <= reflect.Int64
+	isUint := kind >= reflect.Uint && kind <= reflect.Uint64
+	isNumber := isInt || isUint || kind == reflect.Float32 || kind == reflect.Float64
+	if parsed.widget == "" {
+		switch {
+		case parsed.options != nil:
+			parsed.widget = "selector"
+		case kind == reflect.Bool:
+			parsed.widget = "checkbox"
+		case isNumber:
+			parsed.widget = "number"
+		case kind == reflect.String:
+			parsed.widget = "textbox"
+		}
+	}
+	switch parsed.widget {
+	case "checkbox":
+		if kind != reflect.Bool {
+			return fail("a checkbox needs a bool rather than %s", field.Type)
+		}
+	case "slider", "number":
+		if !isNumber {
+			return fail("a %s needs a number rather than %s", parsed.widget, field.Type)
+		}
+		if !hasMin {
+			parsed.min = -math.MaxFloat64
+			if isUint {
+				parsed.min = 0
+			}
+		}
+		if !hasMax {
+			parsed.max = math.MaxFloat64
+		}
+		if parsed.widget == "slider" && (!hasMin || !hasMax) {
+			return fail("a slider needs a min and a max")
+		}
+		if parsed.min > parsed.max || parsed.step <= 0 {
+			return fail("the range %v to %v in steps of %v is empty", parsed.min, parsed.max, parsed.step)
+		}
+	case "selector":
+		if len(parsed.options) == 0 {
+			return fail("a selector needs options")
+		}
+		if kind != reflect.String && !isInt {
+			return fail("a selector needs a string or an integer rather than %s", field.Type)
+		}
+	case "textbox":
+		if kind != reflect.String {
+			return fail("a textbox needs a string rather than %s", field.Type)
+		}
+	default:
+		return fail("there is no widget for %s", field.Type)
+	}
+	return parsed, nil
+}
+
+// bind shows the struct's values in the widgets, following changes made by the game.  Edits are ignored
+// by the bindings since they only reach the struct through Apply, which keeps Cancel working.
+func (s *SettingsMenu) bind() {
+	for _, f := range s.fields {
+		binding, _ := BindPointer(s.target.Field(f.index).Addr().Interface())
+		binding.set = func(value interface{}) error { return nil }
+		switch {
+		case f.checkbox != nil:
+			f.checkbox.Bind(binding)
+		case f.slider != nil:
+			f.slider.Bind(binding)
+		case f.selector != nil:
+			f.selector.Bind(binding)
+		case f.textbox != nil:
+			f.textbox.Bind(binding)
+		}
+	}
+}
+
+// Reset loads the widgets from the struct, discarding any edits
+func (s *SettingsMenu) Reset() {
+	for _, f := range s.fields {
+		value := s.target.Field(f.index)
+		switch {
+		case f.checkbox != nil:
+			f.checkbox.Set(value.Bool())
+		case f.slider != nil:
+			f.slider.Set(numberOf(value))
+		case f.selector != nil:
+			if value.Kind() == reflect.String {
+				index := 0
+				for i, option := range f.selector.Options {
+					if option == value.String() {
+						index = i
+					}
+				}
+				f.selector.Set(index)
+			} else {
+				f.selector.Set(int(value.Int()))
+			}
+		case f.textbox != nil:
+			f.textbox.SetString(value.String())
+		}
+	}
+}
+
+// Apply copies the values of the widgets into the struct and calls OnApply
+func (s *SettingsMenu) Apply() {
+	for _, f := range s.fields {
+		value := s.target.Field(f.index)
+		switch {
+		case f.checkbox != nil:
+			value.SetBool(f.checkbox.Value)
+		case f.slider != nil:
+			setNumber(value, f.slider.Value)
+		case f.selector != nil:
+			if value.Kind() == reflect.String {
+				value.SetString(f.selector.Selected())
+			} else {
+				value.SetInt(int64(f.selector.Index))
+			}
+		case f.textbox != nil:
+			value.SetString(f.textbox.Value())
+		}
+	}
+	if s.OnApply != nil {
+		s.OnApply()
+	}
+}
+
+// Cancel discards any edits, calls OnCancel and navigates back
+func (s *SettingsMenu) Cancel() {
+	s.Reset()
+	if s.OnCancel != nil {
+		s.OnCancel()
+	}
+	mm := s.Menu.MenuManager
+	if mm.Current() == s.Menu {
+		mm.Pop()
+	} else {
+		s.Menu.Hide()
+	}
+}
+
+func numberOf(value reflect.Value) float64 {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint())
+	}
+	return value.Float()
+}
+
+// setNumber rounds number for integers and clamps it to the range of the value's type
+func setNumber(value reflect.Value, number float64) {
+	bits := uint(value.Type().Bits())
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		max := int64(1)<<(bits-1) - 1
+		switch {
+		case number >= float64(max):
+			value.SetInt(max)
+		case number <= float64(-max-1):
+			value.SetInt(-max - 1)
+		default:
+			value.SetInt(int64(math.Floor(number + 0.5)))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		max := uint64(1)<<bits - 1
+		switch {
+		case number >= float64(max):
+			value.SetUint(max)
+		case number <= 0:
+			value.SetUint(0)
+		default:
+			value.SetUint(uint64(math.Floor(number + 0.5)))
+		}
+	default:
+		if bits == 32 {
+			number = math.Max(-math.MaxFloat32, math.Min(math.MaxFloat32, number))
+		}
+		value.SetFloat(number)
+	}
+}
@@ -0,0 +1,155 @@
+package glmenu
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+type testSettings struct {
+	Volume     int     `menu:"Volume,slider,min=0,max=100,step=5"`
+	Gamma      float64 `menu:",min=0.5,max=2,step=0.1"`
+	Fullscreen bool    `menu:"Fullscreen"`
+	Difficulty string  `menu:"Difficulty,options=Easy|Normal|Hard"`
+	Ignored    int
+}
+
+func TestParseSettingTag(t *testing.T) {
+	settings := reflect.TypeOf(testSettings{})
+	tests := map[string]settingTag{
+		"Volume":     {label: "Volume", widget: "slider", min: 0, max: 100, step: 5, width: promptWidth},
+		"Gamma":      {label: "Gamma", widget: "number", min: 0.5, max: 2, step: 0.1, width: promptWidth},
+		"Fullscreen": {label: "Fullscreen", widget: "checkbox", step: 1, width: promptWidth},
+	}
+	for name, expected := range tests {
+		field, _ := settings.FieldByName(name)
+		parsed, err := parseSettingTag(field, field.Tag.Get("menu"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(parsed, expected) {
+			t.Errorf("%s: expected %+v, got %+v", name, expected, parsed)
+		}
+	}
+	field, _ := settings.FieldByName("Difficulty")
+	if parsed, _ := parseSettingTag(field, field.Tag.Get("menu")); parsed.widget != "selector" || len(parsed.options) != 3 {
+		t.Error("options should make a selector", parsed)
+	}
+
+	field, _ = settings.FieldByName("Ignored")
+	errors := map[string]string{
+		"Ignored,checkbox":            "The setting Ignored: a checkbox needs a bool rather than int",
+		"Ignored,slider,min=0":        "The setting Ignored: a slider needs a min and a max",
+		"Ignored,min=ten":             "The setting Ignored: 'ten' is not a number",
+		"Ignored,colour=red":          "The setting Ignored: unknown option 'colour=red'",
+		"Ignored,min=5,max=1":         "The setting Ignored: the range 5 to 1 in steps of 1 is empty",
+		"Ignored,options=a|b,textbox": "The setting Ignored: a textbox needs a string rather than int",
+	}
+	for tag, expected := range errors {
+		if _, err := parseSettingTag(field, tag); err == nil || err.Error() != expected {
+			t.Errorf("%s: expected %q, got %v", tag, expected, err)
+		}
+	}
+}
+
+func TestSettingsMenuApply(t *testing.T) {
+	openGLContext()
+
+	menu := &Menu{}
+	label := func() *Label { return newTestLabel(menu, "", LabelConfig{}) }
+	settings := &testSettings{Volume: 40, Fullscreen: true, Difficulty: "Hard"}
+	s := &SettingsMenu{Menu: menu, target: reflect.ValueOf(settings).Elem()}
+	volume := &settingField{index: 0, slider: &Slider{Label: label(), Text: "Volume", Min: 0, Max: 100, Step: 5}}
+	fullscreen := &settingField{index: 2, checkbox: &Checkbox{Label: label(), Text: "Fullscreen"}}
+	difficulty := &settingField{index: 3, selector: &Selector{Label: label(), Text: "Difficulty", Options: []string{"Easy", "Normal", "Hard"}}}
+	s.fields = []*settingField{volume, fullscreen, difficulty}
+
+	s.Reset()
+	if volume.slider.Value != 40 || !fullscreen.checkbox.Value || difficulty.selector.Index != 2 {
+		t.Fatal("widgets should show the struct values")
+	}
+	if volume.slider.Label.Text.String != "Volume < 40 >" {
+		t.Error(volume.slider.Label.Text.String)
+	}
+
+	volume.slider.Set(45)
+	fullscreen.checkbox.Set(false)
+	difficulty.selector.Set(0)
+	if settings.Volume != 40 {
+		t.Error("edits should wait for Apply")
+	}
+	applied := false
+	s.OnApply = func() { applied = true }
+	s.Apply()
+	if !applied || settings.Volume != 45 || settings.Fullscreen || settings.Difficulty != "Easy" {
+		t.Errorf("unexpected settings %+v", settings)
+	}
+
+	volume.slider.Set(100)
+	s.Reset()
+	if volume.slider.Value != 45 {
+		t.Error("reset should discard edits", volume.slider.Value)
+	}
+}
+
+func TestSettingsMenuBinding(t *testing.T) {
+	openGLContext()
+
+	menu := &Menu{}
+	settings := &testSettings{Volume: 40}
+	s := &SettingsMenu{Menu: menu, target: reflect.ValueOf(settings).Elem()}
+	volume := &Slider{Label: newTestLabel(menu, "", LabelConfig{}), Text: "Volume", Min: 0, Max: 100, Step: 5}
+	menu.Labels = []*Label{volume.Label}
+	s.fields = []*settingField{{index: 0, slider: volume}}
+	s.bind()
+	if volume.Value != 40 {
+		t.Fatal("binding should show the struct value", volume.Value)
+	}
+
+	volume.Set(45)
+	volume.Label.binding.push(volume.Value)
+	menu.refreshBindings()
+	if settings.Volume != 40 || volume.Value != 45 {
+		t.Error("edits should wait for Apply", settings.Volume, volume.Value)
+	}
+	settings.Volume = 10
+	menu.refreshBindings()
+	if volume.Value != 10 {
+		t.Error("changes made by the game should be shown", volume.Value)
+	}
+}
+
+func TestSliderSnap(t *testing.T) {
+	slider := &Slider{Min: 0, Max: 2, Step: 0.1}
+	value := 0.0
+	for i := 0; i < 12; i++ {
+		value = slider.snap(value + slider.Step)
+	}
+	if value != 1.2 {
+		t.Error("stepping should not accumulate float error", value)
+	}
+	if value = (&Slider{Min: 0.5, Step: 0.25}).snap(1.3); value != 1.25 {
+		t.Error("values should snap to Min + n*Step", value)
+	}
+	if value = (&Slider{Min: -math.MaxFloat64, Step: 5}).snap(12); value != 10 {
+		t.Error("unbounded sliders should step from zero", value)
+	}
+}
+
+func TestSetNumber(t *testing.T) {
+	var small int8
+	var unsigned uint8
+	var large int64
+	var float float32
+	setNumber(reflect.ValueOf(&small).Elem(), 300)
+	setNumber(reflect.ValueOf(&unsigned).Elem(), -3)
+	setNumber(reflect.ValueOf(&large).Elem(), math.MaxFloat64)
+	setNumber(reflect.ValueOf(&float).Elem(), -math.MaxFloat64)
+	if small != math.MaxInt8 || unsigned != 0 || large != math.MaxInt64 || float != -math.MaxFloat32 {
+		t.Error("numbers should be clamped to the range of their type", small, unsigned, large, float)
+	}
+	setNumber(reflect.ValueOf(&small).Elem(), -2.6)
+	if small != -3 {
+		t.Error("integers should be rounded", small)
+	}
+}
@@ -41,15 +41,31 @@ type TextBoxInteraction func(
 )
 
 type TextBox struct {
+	ID                 string // key of the saved value, see ValueFile
 	Menu               *Menu
 	Text               *v41.Text
 	Cursor             *v41.Text
 	CursorIndex        int   // position of the cursor within the text
 	CursorBarFrequency int64 // how long does each flash cycle last (visible -> invisible -> visible)
 	MaxLength          int
-	Time               time.Time
-	IsEdit             bool
-	IsClick            bool
+	Placeholder        *v41.Text // drawn while the textbox is empty and not being edited
+	PlaceholderKey     string    // translation key, see SetPlaceholderKey
+	Direction          Direction // see SetDirection
+	TabIndex           int       // see Menu.FocusNext
+	Theme              *Theme    // overrides the menu's theme
+	placeholder        string    // in logical order
+	placeholderArgs    []interface{}
+	binding            bound
+
+	// bidirectional text is displayed in a different order than it is edited.  These are only set when
+	// the order differs, otherwise Text.String holds the value as typed.
+	value    []rune // in logical order
+	levels   []int  // embedding level of each rune in value, odd levels running right to left
+	toVisual []int  // display index of each rune in value
+	Time     time.Time
+	IsEdit   bool
+	IsClick  bool
+	disabled bool // see SetEnabled
 
 	// user defined
 	OnClick    TextBoxInteraction
@@ -80,6 +96,8 @@ type TextBox struct {
 	X1          Point
 	X2          Point
 	BorderWidth int32
+	Size        mgl32.Vec2 // logical dimensions requested when loading
+	border      float32    // framebuffer pixel values
 	height      float32
 	width       float32
 
@@ -110,15 +128,12 @@ func (textbox *TextBox) Load(menu *Menu, width, height float32, borderWidth int3
 	textbox.Text = v41.NewText(menu.Font, 1.0, 1.1)
 	textbox.Cursor = v41.NewText(menu.Font, 1.0, 1.1)
 	textbox.Cursor.SetString("|")
+	textbox.Placeholder = v41.NewText(menu.Font, 1.0, 1.1)
 
 	// border formatting
 	textbox.BorderWidth = borderWidth
-	textbox.height = height
-	textbox.width = width
-	textbox.X1.X = -float32(width) / 2.0
-	textbox.X1.Y = -float32(height) / 2.0
-	textbox.X2.X = float32(width) / 2.0
-	textbox.X2.Y = float32(height) / 2.0
+	textbox.Size = mgl32.Vec2{width, height}
+	textbox.scaleBox()
 	textbox.borderBackground = mgl32.Vec3{1.0, 1.0, 1.0}
 	textbox.textBackground = mgl32.Vec3{0.0, 0.0, 0.0}
 
@@ -149,8 +164,6 @@ func (textbox *TextBox) Load(menu *Menu, width, height float32, borderWidth int3
 	gl.GenBuffers(1, &textbox.vbo)
 	gl.GenBuffers(1, &textbox.ebo)
 
-	glfloatSize := int32(4)
-
 	// vao
 	gl.BindVertexArray(textbox.vao)
 
@@ -166,15 +179,44 @@ func (textbox *TextBox) Load(menu *Menu, width, height float32, borderWidth int3
 		0,
 		gl.PtrOffset(0),
 	)
-	gl.BufferData(gl.ARRAY_BUFFER, int(glfloatSize)*textbox.vboIndexCount, gl.Ptr(textbox.vboData), gl.DYNAMIC_DRAW)
+	gl.BindVertexArray(0)
+	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
+
+	textbox.upload()
+	return
+}
+
+// scaleBox converts the logical Size and BorderWidth into framebuffer pixels
+func (textbox *TextBox) scaleBox() {
+	scale := textbox.Menu.ContentScale
+	textbox.border = float32(textbox.BorderWidth) * scale
+	textbox.width = textbox.Size.X() * scale
+	textbox.height = textbox.Size.Y() * scale
+	textbox.X1.X = -textbox.width / 2.0
+	textbox.X1.Y = -textbox.height / 2.0
+	textbox.X2.X = textbox.width / 2.0
+	textbox.X2.Y = textbox.height / 2.0
+}
+
+// rescale rebuilds the box geometry after the menu's content scale has changed
+func (textbox *TextBox) rescale() {
+	textbox.scaleBox()
+	textbox.makeBufferData()
+	textbox.upload()
+}
+
+func (textbox *TextBox) upload() {
+	glfloatSize := 4
 
+	gl.BindVertexArray(textbox.vao)
+	gl.BindBuffer(gl.ARRAY_BUFFER, textbox.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, glfloatSize*textbox.vboIndexCount, gl.Ptr(textbox.vboData), gl.DYNAMIC_DRAW)
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, textbox.ebo)
-	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, int(glfloatSize)*textbox.eboIndexCount, gl.Ptr(textbox.eboData), gl.DYNAMIC_DRAW)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, glfloatSize*textbox.eboIndexCount, gl.Ptr(textbox.eboData), gl.DYNAMIC_DRAW)
 	gl.BindVertexArray(0)
 
 	gl.BindBuffer(gl.ARRAY_BUFFER, 0)
 	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, 0)
-	return
 }
 
 // X1: lower left hand point
@@ -193,20 +235,20 @@ func (textbox *TextBox) makeBufferData() {
 	// 6,7 (3) -> fourth point
 	// one triangle is drawn using 0,1,2 and the next using 0,2,3 - this pattern applies to all edges (left, right, top, bottom)
 	textbox.vboData[0] = textbox.X1.X
-	textbox.vboData[1] = textbox.X2.Y + float32(textbox.BorderWidth)
-	textbox.vboData[2] = textbox.X1.X - float32(textbox.BorderWidth)
-	textbox.vboData[3] = textbox.X2.Y + float32(textbox.BorderWidth)
-	textbox.vboData[4] = textbox.X1.X - float32(textbox.BorderWidth)
-	textbox.vboData[5] = textbox.X1.Y - float32(textbox.BorderWidth)
+	textbox.vboData[1] = textbox.X2.Y + textbox.border
+	textbox.vboData[2] = textbox.X1.X - textbox.border
+	textbox.vboData[3] = textbox.X2.Y + textbox.border
+	textbox.vboData[4] = textbox.X1.X - textbox.border
+	textbox.vboData[5] = textbox.X1.Y - textbox.border
 	textbox.vboData[6] = textbox.X1.X
-	textbox.vboData[7] = textbox.X1.Y - float32(textbox.BorderWidth)
+	textbox.vboData[7] = textbox.X1.Y - textbox.border
 	textbox.eboData[0], textbox.eboData[1], textbox.eboData[2], textbox.eboData[3], textbox.eboData[4], textbox.eboData[5] = 0, 1, 2, 0, 2, 3
 
 	// top border edge - intentionally leaves out the borderwidth on the x-axis
 	textbox.vboData[8] = textbox.X2.X
-	textbox.vboData[9] = textbox.X2.Y + float32(textbox.BorderWidth)
+	textbox.vboData[9] = textbox.X2.Y + textbox.border
 	textbox.vboData[10] = textbox.X1.X
-	textbox.vboData[11] = textbox.X2.Y + float32(textbox.BorderWidth)
+	textbox.vboData[11] = textbox.X2.Y + textbox.border
 	textbox.vboData[12] = textbox.X1.X
 	textbox.vboData[13] = textbox.X2.Y
 	textbox.vboData[14] = textbox.X2.X
@@ -219,20 +261,20 @@ func (textbox *TextBox) makeBufferData() {
 	textbox.vboData[18] = textbox.X1.X
 	textbox.vboData[19] = textbox.X1.Y
 	textbox.vboData[20] = textbox.X1.X
-	textbox.vboData[21] = textbox.X1.Y - float32(textbox.BorderWidth)
+	textbox.vboData[21] = textbox.X1.Y - textbox.border
 	textbox.vboData[22] = textbox.X2.X
-	textbox.vboData[23] = textbox.X1.Y - float32(textbox.BorderWidth)
+	textbox.vboData[23] = textbox.X1.Y - textbox.border
 	textbox.eboData[12], textbox.eboData[13], textbox.eboData[14], textbox.eboData[15], textbox.eboData[16], textbox.eboData[17] = 8, 9, 10, 8, 10, 11
 
 	// right border edge
-	textbox.vboData[24] = textbox.X2.X + float32(textbox.BorderWidth)
-	textbox.vboData[25] = textbox.X2.Y + float32(textbox.BorderWidth)
+	textbox.vboData[24] = textbox.X2.X + textbox.border
+	textbox.vboData[25] = textbox.X2.Y + textbox.border
 	textbox.vboData[26] = textbox.X2.X
-	textbox.vboData[27] = textbox.X2.Y + float32(textbox.BorderWidth)
+	textbox.vboData[27] = textbox.X2.Y + textbox.border
 	textbox.vboData[28] = textbox.X2.X
-	textbox.vboData[29] = textbox.X1.Y - float32(textbox.BorderWidth)
-	textbox.vboData[30] = textbox.X2.X + float32(textbox.BorderWidth)
-	textbox.vboData[31] = textbox.X1.Y - float32(textbox.BorderWidth)
+	textbox.vboData[29] = textbox.X1.Y - textbox.border
+	textbox.vboData[30] = textbox.X2.X + textbox.border
+	textbox.vboData[31] = textbox.X1.Y - textbox.border
 	textbox.eboData[18], textbox.eboData[19], textbox.eboData[20], textbox.eboData[21], textbox.eboData[22], textbox.eboData[23] = 12, 13, 14, 12, 14, 15
 
 	// background
@@ -247,17 +289,148 @@ func (textbox *TextBox) makeBufferData() {
 	textbox.eboData[24], textbox.eboData[25], textbox.eboData[26], textbox.eboData[27], textbox.eboData[28], textbox.eboData[29] = 16, 17, 18, 16, 18, 19
 }
 
+func (textbox *TextBox) Release() {
+	gl.DeleteBuffers(1, &textbox.vbo)
+	gl.DeleteBuffers(1, &textbox.ebo)
+	gl.DeleteVertexArrays(1, &textbox.vao)
+	gl.DeleteProgram(textbox.program)
+	textbox.Text.Release()
+	textbox.Cursor.Release()
+	textbox.Placeholder.Release()
+}
+
 func (textbox *TextBox) SetColor(color mgl32.Vec3) {
 	textbox.Text.SetColor(color)
 	textbox.Cursor.SetColor(color)
+	textbox.Placeholder.SetColor(textbox.style(StyleDisabled).TextColor)
+}
+
+// SetPlaceholder shows str while the textbox is empty
+func (textbox *TextBox) SetPlaceholder(str string, argv ...interface{}) {
+	textbox.PlaceholderKey = ""
+	textbox.setPlaceholder(sprintf(str, argv...))
+}
+
+// setPlaceholder shows str, which has already been formatted or translated, in the textbox's direction
+func (textbox *TextBox) setPlaceholder(str string) {
+	textbox.placeholder = str
+	textbox.Placeholder.SetString(textbox.Menu.display(textbox.Direction, str))
 }
 
 func (textbox *TextBox) SetString(str string, argv ...interface{}) {
-	if len(argv) == 0 {
-		textbox.Text.SetString(str)
-	} else {
-		textbox.Text.SetString(str, argv...)
+	textbox.setValue([]rune(sprintf(str, argv...)))
+	if textbox.CursorIndex > len(textbox.runes()) {
+		textbox.CursorIndex = len(textbox.runes())
+	}
+}
+
+// Value returns the text in the order it was typed
+func (textbox *TextBox) Value() string {
+	return string(textbox.runes())
+}
+
+// SetDirection changes how the text is displayed and how the cursor keys move through it
+func (textbox *TextBox) SetDirection(direction Direction) {
+	textbox.Direction = direction
+	textbox.setValue(textbox.runes())
+	textbox.setPlaceholder(textbox.placeholder)
+	textbox.placeCursor()
+}
+
+func (textbox *TextBox) runes() []rune {
+	if textbox.toVisual != nil {
+		return append([]rune(nil), textbox.value...)
 	}
+	return []rune(textbox.Text.String)
+}
+
+// setValue stores the logical text and displays it in visual order
+func (textbox *TextBox) setValue(r []rune) {
+	rtl := textbox.Direction.isRTL(r, textbox.Menu != nil && textbox.Menu.isRTL())
+	visual, levels, toVisual := bidiOrder(r, rtl)
+	textbox.value, textbox.levels, textbox.toVisual = nil, nil, nil
+	for _, level := range levels {
+		if level%2 == 1 {
+			textbox.value, textbox.levels, textbox.toVisual = r, levels, toVisual
+			break
+		}
+	}
+	textbox.Text.SetString(string(visual))
+}
+
+// caretBoundary converts a logical cursor index into the index of the displayed character it is drawn before.
+// The cursor sits just after the character preceding it in logical order, which is on that character's
+// left side when it runs right to left.
+func (textbox *TextBox) caretBoundary(index int) int {
+	if textbox.toVisual == nil || len(textbox.value) == 0 {
+		return index
+	}
+	if index > 0 {
+		v := textbox.toVisual[index-1]
+		if textbox.levels[index-1]%2 == 1 {
+			return v
+		}
+		return v + 1
+	}
+	v := textbox.toVisual[0]
+	if textbox.levels[0]%2 == 1 {
+		return v + 1
+	}
+	return v
+}
+
+// logicalIndex converts the boundary before a displayed character back into a logical cursor index
+func (textbox *TextBox) logicalIndex(boundary int) int {
+	if textbox.toVisual == nil {
+		return boundary
+	}
+	n := len(textbox.value)
+	if n == 0 {
+		return 0
+	}
+	toLogical := make([]int, n)
+	for l, v := range textbox.toVisual {
+		toLogical[v] = l
+	}
+	if boundary < n {
+		l := toLogical[boundary]
+		if textbox.levels[l]%2 == 1 {
+			return l + 1
+		}
+		return l
+	}
+	l := toLogical[n-1]
+	if textbox.levels[l]%2 == 1 {
+		return l
+	}
+	return l + 1
+}
+
+func (textbox *TextBox) placeCursor() {
+	textbox.Cursor.SetPosition(
+		mgl32.Vec2{
+			textbox.Text.Position.X() + float32(textbox.Text.CharPosition(textbox.caretBoundary(textbox.CursorIndex))),
+			textbox.Text.Position.Y(),
+		})
+}
+
+// moveVisual moves the cursor left (negative) or right (positive) across the displayed text
+func (textbox *TextBox) moveVisual(step int) {
+	if textbox.toVisual == nil {
+		textbox.MoveCursor(step)
+		return
+	}
+	n := len(textbox.value)
+	current := textbox.caretBoundary(textbox.CursorIndex)
+	for boundary := current + step; boundary >= 0 && boundary <= n; boundary += step {
+		index := textbox.logicalIndex(boundary)
+		if textbox.caretBoundary(index) != current {
+			textbox.CursorIndex = index
+			break
+		}
+	}
+	textbox.placeCursor()
+	textbox.ImmediateCursorDraw()
 }
 
 func (textbox *TextBox) Draw() {
@@ -287,6 +460,9 @@ func (textbox *TextBox) Draw() {
 	gl.DrawElementsBaseVertex(gl.TRIANGLES, int32(1*6), gl.UNSIGNED_INT, nil, int32(16))
 	gl.BindVertexArray(0)
 
+	if textbox.Text.RuneCount == 0 && !textbox.IsEdit {
+		textbox.Placeholder.Draw()
+	}
 	textbox.Text.Draw()
 	textbox.Cursor.Draw()
 }
@@ -299,9 +475,9 @@ func (textbox *TextBox) KeyRelease(key glfw.Key, withShift bool) {
 		case glfw.KeyEscape:
 			textbox.IsEdit = false
 		case glfw.KeyLeft:
-			textbox.MoveCursor(-1)
+			textbox.moveVisual(-1)
 		case glfw.KeyRight:
-			textbox.MoveCursor(+1)
+			textbox.moveVisual(+1)
 		default:
 			textbox.Edit(key, withShift)
 		}
@@ -321,24 +497,22 @@ func (textbox *TextBox) Edit(key glfw.Key, withShift bool) {
 			} else {
 				theRune = rune(key)
 			}
-			if textbox.Text.MaxRuneCount > 0 && len(textbox.Text.String) == textbox.Text.MaxRuneCount {
+			value := textbox.runes()
+			if textbox.Text.MaxRuneCount > 0 && len(value) == textbox.Text.MaxRuneCount {
 				// too long - do nothing
 			} else {
 				index := textbox.CursorIndex
-				r := make([]rune, len(textbox.Text.String)+1)
-				copy(r, []rune(textbox.Text.String))
+				r := make([]rune, len(value)+1)
+				copy(r, value)
 				copy(r[index+1:], r[index:])
 				r[index] = theRune
 
 				index += 1
 				textbox.CursorIndex = index
-				textbox.Text.SetString(string(r))
+				textbox.setValue(r)
 				textbox.Text.SetPosition(textbox.Text.Position)
-				textbox.Cursor.SetPosition(
-					mgl32.Vec2{
-						textbox.Text.Position.X() + float32(textbox.Text.CharPosition(index)),
-						textbox.Text.Position.Y(),
-					})
+				textbox.placeCursor()
+				textbox.binding.push(textbox.Value())
 			}
 		}
 	}
@@ -353,6 +527,7 @@ func (textbox *TextBox) SetPosition(v mgl32.Vec2) {
 	textbox.Position = v
 	textbox.Text.SetPosition(v)
 	textbox.Cursor.SetPosition(v)
+	textbox.Placeholder.SetPosition(v)
 }
 
 func (textbox *TextBox) GetBoundingBox() (X1, X2 Point) {
@@ -366,31 +541,26 @@ func (textbox *TextBox) GetBoundingBox() (X1, X2 Point) {
 
 func (textbox *TextBox) Backspace() {
 	index := textbox.CursorIndex
-	if len(textbox.Text.String) > 0 {
-		r := make([]rune, len(textbox.Text.String)-1)
-		copy(r, []rune(textbox.Text.String[0:index-1]))
-		copy(r[index-1:], []rune(textbox.Text.String[index:]))
+	value := textbox.runes()
+	if len(value) > 0 && index > 0 {
+		r := make([]rune, len(value)-1)
+		copy(r, value[0:index-1])
+		copy(r[index-1:], value[index:])
 
 		// shift our cursor back
 		index -= 1
 		textbox.CursorIndex = index
-		textbox.Text.SetString(string(r))
+		textbox.setValue(r)
 		textbox.Text.SetPosition(textbox.Text.Position)
-		textbox.Cursor.SetPosition(
-			mgl32.Vec2{
-				textbox.Text.Position.X() + float32(textbox.Text.CharPosition(index)),
-				textbox.Text.Position.Y(),
-			})
+		textbox.placeCursor()
+		textbox.binding.push(textbox.Value())
 	}
 }
 
 func (textbox *TextBox) OrthoToScreenCoord() (X1 Point, X2 Point) {
 	x1, x2 := textbox.GetBoundingBox()
-	X1.X = x1.X + textbox.Menu.WindowWidth/2
-	X1.Y = x1.Y + textbox.Menu.WindowHeight/2
-
-	X2.X = x2.X + textbox.Menu.WindowWidth/2
-	X2.Y = x2.Y + textbox.Menu.WindowHeight/2
+	X1 = textbox.Menu.orthoToScreen(x1.X, x1.Y)
+	X2 = textbox.Menu.orthoToScreen(x2.X, x2.Y)
 	return
 }
 
@@ -410,19 +580,16 @@ func (textbox *TextBox) ImmediateCursorDraw() {
 }
 
 func (textbox *TextBox) MoveCursor(offset int) {
-	if textbox.CursorIndex >= 0 && (textbox.CursorIndex <= len(textbox.Text.String)) {
+	length := len(textbox.runes())
+	if textbox.CursorIndex >= 0 && (textbox.CursorIndex <= length) {
 		textbox.CursorIndex += offset
 		if textbox.CursorIndex < 0 {
 			textbox.CursorIndex = 0
 		}
-		if textbox.CursorIndex > len(textbox.Text.String) {
-			textbox.CursorIndex = len(textbox.Text.String)
+		if textbox.CursorIndex > length {
+			textbox.CursorIndex = length
 		}
-		textbox.Cursor.SetPosition(
-			mgl32.Vec2{
-				textbox.Text.Position.X() + float32(textbox.Text.CharPosition(textbox.CursorIndex)),
-				textbox.Text.Position.Y(),
-			})
+		textbox.placeCursor()
 		textbox.ImmediateCursorDraw()
 	}
 }
@@ -433,8 +600,9 @@ func (textbox *TextBox) IsClicked(xPos, yPos float64, button MouseClick) {
 	// we have to transform them
 	X1, X2 := textbox.OrthoToScreenCoord()
 	inBox := float32(xPos) > X1.X && float32(xPos) < X2.X && float32(yPos) > X1.Y && float32(yPos) < X2.Y
-	if inBox {
-		index, side := textbox.Text.ClickedCharacter(xPos, float64(textbox.Menu.screenPositionOffset[0]))
+	if inBox && !textbox.disabled {
+		// the font works in framebuffer pixels
+		index, side := textbox.Text.ClickedCharacter(xPos*float64(textbox.Menu.ContentScale), float64(textbox.Menu.screenPositionOffset[0]))
 		if side == v41.CSRight {
 			index++
 		}
@@ -442,14 +610,10 @@ func (textbox *TextBox) IsClicked(xPos, yPos float64, button MouseClick) {
 		if side == v41.CSUnknown {
 			index = 0
 		}
-		textbox.CursorIndex = index
+		// the clicked character is counted in display order
+		textbox.CursorIndex = textbox.logicalIndex(index)
 		textbox.ImmediateCursorDraw()
-
-		textbox.Cursor.SetPosition(
-			mgl32.Vec2{
-				textbox.Text.Position.X() + float32(textbox.Text.CharPosition(index)),
-				textbox.Text.Position.Y(),
-			})
+		textbox.placeCursor()
 		textbox.IsClick = true
 		if textbox.OnClick != nil {
 			textbox.OnClick(textbox, xPos, yPos, button, inBox)
@@ -472,11 +636,14 @@ func (textbox *TextBox) IsReleased(xPos, yPos float64, button MouseClick) {
 	textbox.IsClick = false
 }
 
+// NavigateTo starts editing with the cursor after the text.  It is called by Menu.Focus.
+// Textboxes focused by a click keep the cursor where they were clicked.
 func (textbox *TextBox) NavigateTo() {
-	if !textbox.IsEdit {
-		point := textbox.InsidePoint()
-		textbox.IsClicked(float64(point.X), float64(point.Y), MouseLeft)
-		textbox.IsReleased(float64(point.X), float64(point.Y), MouseLeft)
+	if !textbox.IsEdit && !textbox.IsClick && !textbox.disabled {
+		textbox.CursorIndex = len(textbox.runes())
+		textbox.placeCursor()
+		textbox.ImmediateCursorDraw()
+		textbox.IsEdit = true
 	}
 }
 
@@ -495,8 +662,34 @@ func (textbox *TextBox) Follow() bool {
 	return false
 }
 
+// IsNoop reports whether keyboard navigation skips the textbox
 func (textbox *TextBox) IsNoop() bool {
-	return false
+	return textbox.disabled
+}
+
+// SetEnabled greys out a disabled textbox, which can't be edited and is skipped by keyboard navigation
+func (textbox *TextBox) SetEnabled(enabled bool) {
+	textbox.disabled = !enabled
+	if textbox.disabled {
+		if textbox.Menu != nil && textbox.Menu.focused == textbox {
+			textbox.Menu.Blur()
+		}
+		textbox.IsEdit = false
+		textbox.IsClick = false
+	}
+	textbox.SetColor(textbox.style(textbox.restingState()).TextColor)
+}
+
+// IsEnabled reports whether the textbox can be edited, see SetEnabled
+func (textbox *TextBox) IsEnabled() bool {
+	return !textbox.disabled
+}
+
+func (textbox *TextBox) restingState() StyleState {
+	if textbox.disabled {
+		return StyleDisabled
+	}
+	return StyleNormal
 }
 
 func (textbox *TextBox) Type() FormatableType {
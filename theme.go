@@ -0,0 +1,302 @@
+package glmenu
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-gl/mathgl/mgl32"
+	"io/ioutil"
+	"sort"
+)
+
+type StyleState int
+
+const (
+	StyleNormal   StyleState = 0
+	StyleHover               = 1
+	StylePressed             = 2
+	StyleDisabled            = 3
+	StyleFocused             = 4
+)
+
+// Style holds the look of one state.  Unset (nil) properties are inherited.
+// BackgroundColor, BorderColor and Padding describe the menu itself and are only read from the normal style.
+// RingColor is only read from the focused style.
+type Style struct {
+	TextColor       *mgl32.Vec3 `json:"textColor"`
+	BackgroundColor *mgl32.Vec4 `json:"backgroundColor"`
+	BorderColor     *mgl32.Vec4 `json:"borderColor"`
+	Padding         *mgl32.Vec2 `json:"padding"`
+	Scale           *float32    `json:"scale"` // text scale, the hover scale being reached gradually at TextScaleRate
+	RingColor       *mgl32.Vec4 `json:"ringColor"`
+}
+
+// Theme is a set of named styles shared by any number of menus and elements.
+// Properties are looked up on the element's theme, the menu's theme and finally the MenuManager's theme,
+// following each theme's Parent along the way, before falling back to the menu's MenuDefaults.
+type Theme struct {
+	Parent   *Theme `json:"-"`
+	Normal   Style  `json:"normal"`
+	Hover    Style  `json:"hover"`
+	Pressed  Style  `json:"pressed"`
+	Disabled Style  `json:"disabled"`
+	Focused  Style  `json:"focused"`
+}
+
+// ResolvedStyle is a Style with every property filled in
+type ResolvedStyle struct {
+	TextColor       mgl32.Vec3
+	BackgroundColor mgl32.Vec4
+	BorderColor     mgl32.Vec4
+	Padding         mgl32.Vec2
+	Scale           float32
+	RingColor       mgl32.Vec4
+}
+
+func (theme *Theme) Style(state StyleState) *Style {
+	switch state {
+	case StyleHover:
+		return &theme.Hover
+	case StylePressed:
+		return &theme.Pressed
+	case StyleDisabled:
+		return &theme.Disabled
+	case StyleFocused:
+		return &theme.Focused
+	}
+	return &theme.Normal
+}
+
+// Resolve looks up every property of state in the theme and its parents, using defaults for the rest
+func (theme *Theme) Resolve(state StyleState, defaults MenuDefaults) ResolvedStyle {
+	return resolveStyle([]*Theme{theme}, state, defaults)
+}
+
+// resolveStyle searches themes, most specific first, and each of their parents
+func resolveStyle(themes []*Theme, state StyleState, defaults MenuDefaults) ResolvedStyle {
+	resolved := defaultStyle(state, defaults)
+	if state == StyleFocused {
+		// unset focused properties follow the hover style, which themes often set alone
+		hover := resolveStyle(themes, StyleHover, defaults)
+		resolved.TextColor, resolved.Scale = hover.TextColor, hover.Scale
+		if defaults.FocusColor == (mgl32.Vec4{}) {
+			resolved.RingColor = hover.TextColor.Vec4(1)
+		}
+	}
+	var textColor *mgl32.Vec3
+	var scale *float32
+	var background, border, ring *mgl32.Vec4
+	var padding *mgl32.Vec2
+	for _, theme := range themes {
+		for ; theme != nil; theme = theme.Parent {
+			style, normal := theme.Style(state), theme.Style(StyleNormal)
+			if textColor == nil {
+				textColor = style.TextColor
+			}
+			if scale == nil {
+				scale = style.Scale
+			}
+			if ring == nil && state == StyleFocused {
+				ring = style.RingColor
+			}
+			if background == nil {
+				background = normal.BackgroundColor
+			}
+			if border == nil {
+				border = normal.BorderColor
+			}
+			if padding == nil {
+				padding = normal.Padding
+			}
+		}
+	}
+	if textColor != nil {
+		resolved.TextColor = *textColor
+	}
+	if scale != nil {
+		resolved.Scale = *scale
+	}
+	if background != nil {
+		resolved.BackgroundColor = *background
+	}
+	if border != nil {
+		resolved.BorderColor = *border
+	}
+	if padding != nil {
+		resolved.Padding = *padding
+	}
+	if ring != nil {
+		resolved.RingColor = *ring
+	}
+	return resolved
+}
+
+// defaultStyle maps MenuDefaults onto the styles
+func defaultStyle(state StyleState, defaults MenuDefaults) ResolvedStyle {
+	resolved := ResolvedStyle{
+		TextColor:       defaults.TextColor,
+		BackgroundColor: defaults.BackgroundColor,
+		BorderColor:     defaults.BorderColor,
+		Padding:         defaults.Padding,
+		Scale:           1,
+	}
+	switch state {
+	case StyleHover:
+		resolved.TextColor = defaults.TextHover
+		resolved.Scale = labelScaleMax
+	case StyleFocused:
+		resolved.TextColor = defaults.TextHover
+		resolved.Scale = labelScaleMax
+		resolved.RingColor = defaults.FocusColor
+		if resolved.RingColor == (mgl32.Vec4{}) {
+			resolved.RingColor = defaults.TextHover.Vec4(1)
+		}
+	case StylePressed:
+		resolved.TextColor = defaults.TextClick
+		resolved.Scale = labelScaleMax
+	case StyleDisabled:
+		resolved.TextColor = defaults.TextDisabled
+		if resolved.TextColor == (mgl32.Vec3{}) {
+			resolved.TextColor = defaults.TextColor.Mul(0.5)
+		}
+	}
+	return resolved
+}
+
+// themes lists the menu's theme before the MenuManager's
+func (menu *Menu) themes() []*Theme {
+	themes := []*Theme{menu.Theme}
+	if menu.MenuManager != nil {
+		themes = append(themes, menu.MenuManager.Theme)
+	}
+	return themes
+}
+
+func (menu *Menu) style(state StyleState) ResolvedStyle {
+	return resolveStyle(menu.themes(), state, menu.Defaults)
+}
+
+// padding is the logical space between the menu border and its content
+func (menu *Menu) padding() mgl32.Vec2 {
+	return menu.style(StyleNormal).Padding
+}
+
+func (label *Label) style(state StyleState) ResolvedStyle {
+	return resolveStyle(append([]*Theme{label.Theme}, label.Menu.themes()...), state, label.Menu.Defaults)
+}
+
+func (textbox *TextBox) style(state StyleState) ResolvedStyle {
+	return resolveStyle(append([]*Theme{textbox.Theme}, textbox.Menu.themes()...), state, textbox.Menu.Defaults)
+}
+
+// SetTheme replaces the global theme and restyles every menu
+func (mm *MenuManager) SetTheme(theme *Theme) {
+	mm.Theme = theme
+	for _, menu := range mm.sortedMenus() {
+		menu.restyle()
+	}
+	for _, dialog := range mm.dialogs {
+		dialog.restyle()
+	}
+}
+
+// SetTheme replaces the menu's theme, overriding the MenuManager's theme
+func (menu *Menu) SetTheme(theme *Theme) {
+	menu.Theme = theme
+	menu.restyle()
+}
+
+// SetTheme replaces the label's theme, overriding the menu's theme
+func (label *Label) SetTheme(theme *Theme) {
+	label.Theme = theme
+	label.restyle()
+	label.Menu.relayout()
+}
+
+// SetTheme replaces the textbox's theme, overriding the menu's theme
+func (textbox *TextBox) SetTheme(theme *Theme) {
+	textbox.Theme = theme
+	textbox.SetColor(textbox.style(textbox.restingState()).TextColor)
+}
+
+func (menu *Menu) restyle() {
+	for _, label := range menu.Labels {
+		label.restyle()
+	}
+	for _, textbox := range menu.TextBoxes {
+		textbox.SetColor(textbox.style(textbox.restingState()).TextColor)
+	}
+	menu.relayout()
+}
+
+func (label *Label) restyle() {
+	label.IsHover = false
+	label.Text.SetColor(label.style(label.restingState()).TextColor)
+	label.rescale(1)
+}
+
+// rescale sets the resting and hovered text scale, factor being any shrinking needed to fit the menu
+func (label *Label) rescale(factor float32) {
+	label.Text.ScaleMin = factor * label.style(StyleNormal).Scale
+	label.Text.ScaleMax = factor * label.style(StyleHover).Scale
+	label.Text.SetScale(label.Text.ScaleMin)
+}
+
+// themeFile is the layout of a theme file, ie
+//
+//	{"themes": {
+//		"base": {"normal": {"textColor": [1, 1, 1], "padding": [10, 10]}, "hover": {"scale": 1.1}},
+//		"dark": {"parent": "base", "normal": {"backgroundColor": [0.1, 0.1, 0.1, 1]}}
+//	}}
+type themeFile struct {
+	Themes map[string]themeDef `json:"themes"`
+}
+
+type themeDef struct {
+	Parent string `json:"parent"`
+	Theme
+}
+
+// LoadThemes reads the named themes found in the JSON file at path and links each to its parent
+func LoadThemes(path string) (map[string]*Theme, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseThemes(path, data)
+}
+
+func parseThemes(name string, data []byte) (map[string]*Theme, error) {
+	file := themeFile{}
+	if err := decodeJSON(name, data, &file); err != nil {
+		return nil, err
+	}
+
+	themes := make(map[string]*Theme)
+	names := make([]string, 0, len(file.Themes))
+	for themeName, def := range file.Themes {
+		theme := def.Theme
+		themes[themeName] = &theme
+		names = append(names, themeName)
+	}
+	sort.Strings(names)
+	for _, themeName := range names {
+		parent := file.Themes[themeName].Parent
+		if parent == "" {
+			continue
+		}
+		loadErr := &LoadError{File: name}
+		loadErr.Line, loadErr.Column = lineColumn(data, locate(data, "themes", themeName, "parent"))
+		if _, ok := themes[parent]; !ok {
+			loadErr.Err = errors.New(fmt.Sprintf("the parent theme '%s' doesn't exist", parent))
+			return nil, loadErr
+		}
+		themes[themeName].Parent = themes[parent]
+		for theme := themes[parent]; theme != nil; theme = theme.Parent {
+			if theme == themes[themeName] {
+				loadErr.Err = errors.New(fmt.Sprintf("the theme '%s' inherits from itself", themeName))
+				return nil, loadErr
+			}
+		}
+	}
+	return themes, nil
+}
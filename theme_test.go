@@ -0,0 +1,76 @@
+package glmenu
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"testing"
+)
+
+func TestResolveStyle(t *testing.T) {
+	white, red := mgl32.Vec3{1, 1, 1}, mgl32.Vec3{1, 0, 0}
+	dark := mgl32.Vec4{0, 0, 0, 1}
+	scale := float32(1.5)
+	defaults := MenuDefaults{TextColor: mgl32.Vec3{0.5, 0.5, 0.5}, TextHover: mgl32.Vec3{0, 1, 0}, Padding: mgl32.Vec2{10, 10}}
+
+	global := &Theme{Normal: Style{TextColor: &white, BackgroundColor: &dark}}
+	faction := &Theme{Parent: global, Hover: Style{Scale: &scale}}
+	element := &Theme{Normal: Style{TextColor: &red}}
+
+	style := resolveStyle([]*Theme{element, nil, faction}, StyleNormal, defaults)
+	if style.TextColor != red || style.BackgroundColor != dark || style.Padding != defaults.Padding || style.Scale != 1 {
+		t.Error("unexpected normal style", style)
+	}
+	style = resolveStyle([]*Theme{element, nil, faction}, StyleHover, defaults)
+	if style.TextColor != defaults.TextHover || style.Scale != scale || style.BackgroundColor != dark {
+		t.Error("hover properties should come from the hover styles and MenuDefaults", style)
+	}
+	style = resolveStyle(nil, StyleHover, defaults)
+	if style.Scale != labelScaleMax {
+		t.Error("hovered text should grow by default", style.Scale)
+	}
+	style = resolveStyle(nil, StyleDisabled, defaults)
+	if style.TextColor != (mgl32.Vec3{0.25, 0.25, 0.25}) {
+		t.Error("disabled text should be dimmed by default", style.TextColor)
+	}
+	defaults.TextDisabled = red
+	if style = resolveStyle(nil, StyleDisabled, defaults); style.TextColor != red {
+		t.Error("disabled text should use TextDisabled", style.TextColor)
+	}
+
+	hovered := &Theme{Hover: Style{TextColor: &red, Scale: &scale}}
+	style = resolveStyle([]*Theme{hovered}, StyleFocused, MenuDefaults{})
+	if style.TextColor != red || style.Scale != scale || style.RingColor != red.Vec4(1) {
+		t.Error("unset focused properties should follow the hover style", style)
+	}
+	hovered.Focused.TextColor = &white
+	style = resolveStyle([]*Theme{hovered}, StyleFocused, MenuDefaults{FocusColor: dark})
+	if style.TextColor != white || style.RingColor != dark {
+		t.Error("focused properties and FocusColor should win over the hover style", style)
+	}
+}
+
+func TestParseThemes(t *testing.T) {
+	themes, err := parseThemes("themes.json", []byte(`{"themes": {
+		"base": {"normal": {"textColor": [1, 1, 1], "padding": [4, 4]}},
+		"dark": {"parent": "base", "normal": {"backgroundColor": [0, 0, 0, 1]}}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	style := themes["dark"].Resolve(StyleNormal, MenuDefaults{})
+	if themes["dark"].Parent != themes["base"] || style.TextColor != (mgl32.Vec3{1, 1, 1}) || style.Padding != (mgl32.Vec2{4, 4}) {
+		t.Error("dark should inherit from base", style)
+	}
+
+	tests := []struct {
+		data, expected string
+	}{
+		{"{\"themes\": {\n\t\"dark\": {\"parent\": \"light\"}\n}}", "themes.json:2:21: the parent theme 'light' doesn't exist"},
+		{"{\"themes\": {\n\t\"a\": {\"parent\": \"b\"},\n\t\"b\": {\"parent\": \"a\"}\n}}", "themes.json:3:18: the theme 'b' inherits from itself"},
+	}
+	for _, test := range tests {
+		_, err := parseThemes("themes.json", []byte(test.data))
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("expected %q, got %v", test.expected, err)
+		}
+	}
+}
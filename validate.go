@@ -0,0 +1,145 @@
+package glmenu
+
+import (
+	"fmt"
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"sort"
+	"strings"
+)
+
+// ValidationError lists every problem found in the menu graph
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return "Invalid menu setup: " + e.Problems[0]
+	}
+	return fmt.Sprintf("Invalid menu setup, %d problems:\n\t%s", len(e.Problems), strings.Join(e.Problems, "\n\t"))
+}
+
+func (e *ValidationError) add(format string, argv ...interface{}) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, argv...))
+}
+
+// Validate checks the menu graph for mistakes that would otherwise only show up during play:
+// a missing StartMenu, GOTO_MENU labels naming menus that don't exist, menus stored under a
+// name other than their own, duplicate names and empty menus.
+// Menus that can't be reached from the StartMenu, their own ShowOnKey or by being visible already are
+// reported by Unreachable.  Since menus may also be opened by code, ie with Show or Push, they only
+// cause Validate to fail when RequireReachable is set and are otherwise logged with MenuDebug.
+// The returned error is a *ValidationError or nil.
+func (mm *MenuManager) Validate() error {
+	problems := &ValidationError{}
+	if mm.StartMenu == "" {
+		problems.add("no StartMenu has been set")
+	} else if _, ok := mm.Menus[mm.StartMenu]; !ok {
+		problems.add("the StartMenu '%s' doesn't exist", mm.StartMenu)
+	}
+
+	names := make(map[string]map[*Menu]bool)
+	for _, key := range sortedNames(mm.Menus) {
+		menu := mm.Menus[key]
+		if names[menu.Name] == nil {
+			names[menu.Name] = make(map[*Menu]bool)
+		}
+		if names[menu.Name][menu] {
+			continue
+		}
+		names[menu.Name][menu] = true
+		if key != menu.Name {
+			problems.add("the menu '%s' is stored under the name '%s'", menu.Name, key)
+		}
+		if len(menu.Formatable) == 0 {
+			problems.add("the menu '%s' is empty", menu.Name)
+		}
+		for _, label := range menu.Labels {
+			if label.Config.Action != GOTO_MENU {
+				continue
+			}
+			if _, ok := mm.Menus[label.Config.Goto]; !ok {
+				problems.add("the label '%s' in menu '%s' goes to the menu '%s' which doesn't exist", label.String(), menu.Name, label.Config.Goto)
+			}
+		}
+	}
+	var duplicates []string
+	for name, menus := range names {
+		if len(menus) > 1 {
+			duplicates = append(duplicates, name)
+		}
+	}
+	sort.Strings(duplicates)
+	for _, name := range duplicates {
+		problems.add("the name '%s' is used by %d menus", name, len(names[name]))
+	}
+
+	for _, name := range mm.Unreachable() {
+		if len(names[name]) > 1 {
+			continue
+		}
+		problem := fmt.Sprintf("the menu '%s' can't be reached", name)
+		if mm.RequireReachable {
+			problems.Problems = append(problems.Problems, problem)
+		} else {
+			MenuDebug(problem)
+		}
+	}
+
+	if len(problems.Problems) == 0 {
+		return nil
+	}
+	return problems
+}
+
+// Unreachable lists, by name, the menus that no GOTO_MENU label, StartMenu or ShowOnKey leads to.
+// Non-modal menus, which the game shows itself, are always considered reachable.
+func (mm *MenuManager) Unreachable() []string {
+	reached := mm.reachable()
+	var names []string
+	for _, menu := range mm.sortedMenus() {
+		if !reached[menu] {
+			names = append(names, menu.Name)
+			reached[menu] = true
+		}
+	}
+	return names
+}
+
+// reachable follows GOTO_MENU labels outwards from every menu that can be shown without one
+func (mm *MenuManager) reachable() map[*Menu]bool {
+	reached := make(map[*Menu]bool)
+	var queue []*Menu
+	visit := func(menu *Menu) {
+		if menu != nil && !reached[menu] {
+			reached[menu] = true
+			queue = append(queue, menu)
+		}
+	}
+	visit(mm.Menus[mm.StartMenu])
+	for _, menu := range mm.sortedMenus() {
+		if !menu.IsModal || menu.IsVisible || menu.ShowOnKey != glfw.KeyUnknown {
+			visit(menu)
+		}
+	}
+	for len(queue) > 0 {
+		menu := queue[0]
+		queue = queue[1:]
+		for _, label := range menu.Labels {
+			if label.Config.Action == GOTO_MENU {
+				visit(mm.Menus[label.Config.Goto])
+			}
+		}
+	}
+	return reached
+}
+
+// sortedNames is used to report problems in a stable order
+func sortedNames(menus map[string]*Menu) []string {
+	names := make([]string, 0, len(menus))
+	for name := range menus {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
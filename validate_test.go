@@ -0,0 +1,76 @@
+package glmenu
+
+import (
+	"testing"
+)
+
+func gotoLabel(menu *Menu, to string) {
+	label := newTestLabel(menu, to, LabelConfig{Action: GOTO_MENU, Goto: to})
+	menu.Labels = append(menu.Labels, label)
+	menu.Formatable = append(menu.Formatable, label)
+}
+
+func TestMenuManagerValidate(t *testing.T) {
+	openGLContext()
+
+	mm := newTestManager("main", "option")
+	mm.StartMenu = "main"
+	gotoLabel(mm.Menus["main"], "option")
+	gotoLabel(mm.Menus["option"], "main")
+	if err := mm.Validate(); err != nil {
+		t.Fatal("expected a valid graph", err)
+	}
+
+	mm = newTestManager("main", "option", "orphan", "empty", "hud")
+	mm.StartMenu = "start"
+	gotoLabel(mm.Menus["main"], "option")
+	gotoLabel(mm.Menus["option"], "missing")
+	gotoLabel(mm.Menus["orphan"], "main")
+	gotoLabel(mm.Menus["hud"], "empty")
+	mm.Menus["hud"].IsModal = false
+	mm.Menus["copy"] = &Menu{Name: "option", IsModal: true, Formatable: []Formatable{&Label{}}}
+
+	err := mm.Validate()
+	if err == nil {
+		t.Fatal("expected problems")
+	}
+	expected := []string{
+		"the StartMenu 'start' doesn't exist",
+		"the menu 'option' is stored under the name 'copy'",
+		"the menu 'empty' is empty",
+		"the label 'missing' in menu 'option' goes to the menu 'missing' which doesn't exist",
+		"the name 'option' is used by 2 menus",
+	}
+	unreachable := []string{
+		"the menu 'main' can't be reached",
+		"the menu 'orphan' can't be reached",
+	}
+	checkProblems(t, err.(*ValidationError).Problems, expected)
+	if names := mm.Unreachable(); len(names) != 2 || names[0] != "main" || names[1] != "orphan" {
+		t.Error("unreachable menus should only be warned about", names)
+	}
+
+	mm.RequireReachable = true
+	err = mm.Validate()
+	checkProblems(t, err.(*ValidationError).Problems, append(expected, unreachable...))
+
+	mm = newTestManager("main", "code")
+	mm.StartMenu = "main"
+	gotoLabel(mm.Menus["main"], "main")
+	gotoLabel(mm.Menus["code"], "main")
+	if err := mm.Validate(); err != nil {
+		t.Error("menus opened from code should only be warned about", err)
+	}
+}
+
+func checkProblems(t *testing.T, problems, expected []string) {
+	t.Helper()
+	if len(problems) != len(expected) {
+		t.Fatal("unexpected problems", problems)
+	}
+	for i := range expected {
+		if problems[i] != expected[i] {
+			t.Errorf("problem %d: expected %q, got %q", i, expected[i], problems[i])
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package glmenu
+
+import (
+	"github.com/go-gl/glfw/v3.2/glfw"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// Watcher rebuilds the menus of a menu file whenever the file changes, which is handy while
+// working on a layout.  It polls so that it works on every platform: call Poll once per frame.
+type Watcher struct {
+	MenuManager *MenuManager
+	Window      *glfw.Window
+	Path        string
+	Registry    Registry
+	Interval    time.Duration // minimum time between checks of the file
+	OnError     func(error)   // receives load errors.  Defaults to MenuDebug.
+	OnReload    func([]*Menu) // called after the menus have been rebuilt
+
+	names     []string // menus created from the file
+	modTime   time.Time
+	size      int64
+	lastCheck time.Time
+	statErr   string // the last error reported by Poll for the file itself, see Poll
+}
+
+// Watch loads the menu file at path and returns a Watcher that keeps its menus up to date
+func (mm *MenuManager) Watch(window *glfw.Window, path string, registry Registry) (*Watcher, error) {
+	w := &Watcher{
+		MenuManager: mm,
+		Window:      window,
+		Path:        path,
+		Registry:    registry,
+		Interval:    500 * time.Millisecond,
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	menus, err := mm.LoadFile(window, path, registry)
+	if err != nil {
+		return nil, err
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	w.remember(menus)
+	return w, nil
+}
+
+// Poll reloads the file when its modification time or size has changed since the last load.
+// Errors are passed to OnError and leave the current menus in place.  Returns true after a reload.
+// A file that can't be found, ie while an editor replaces it, is reported once until it is back.
+func (w *Watcher) Poll() bool {
+	now := time.Now()
+	if now.Sub(w.lastCheck) < w.Interval {
+		return false
+	}
+	w.lastCheck = now
+
+	info, err := os.Stat(w.Path)
+	if err != nil {
+		if err.Error() != w.statErr {
+			w.statErr = err.Error()
+			w.fail(err)
+		}
+		return false
+	}
+	w.statErr = ""
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	if err := w.Reload(); err != nil {
+		w.fail(err)
+		return false
+	}
+	return true
+}
+
+// Reload rebuilds the menus from the file.  Menus that were visible stay visible, the navigation
+// history is kept and keyboard navigation returns to the same element where it still exists.
+func (w *Watcher) Reload() error {
+	mm := w.MenuManager
+	data, err := ioutil.ReadFile(w.Path)
+	if err != nil {
+		return err
+	}
+	replacing := make(map[string]bool)
+	for _, name := range w.names {
+		replacing[name] = true
+	}
+	defs, err := mm.parseMenus(w.Path, data, w.Registry, replacing)
+	if err != nil {
+		return err
+	}
+
+	old := make(map[string]*Menu)
+	for _, name := range w.names {
+		if menu, ok := mm.Menus[name]; ok {
+			old[name] = menu
+			delete(mm.Menus, name)
+		}
+	}
+	menus, err := mm.build(w.Window, defs)
+	if err != nil {
+		// build has released the menus it created, so every previous menu can be put back as it was
+		for name, menu := range old {
+			mm.Menus[name] = menu
+		}
+		return err
+	}
+	// menus new to the file take the alignment of the first menu that was loaded before
+	align := AlignCenter
+	for _, name := range w.names {
+		if menu, ok := old[name]; ok {
+			align = menu.align
+			break
+		}
+	}
+	replaced := make(map[*Menu]*Menu)
+	for _, menu := range menus {
+		previous, ok := old[menu.Name]
+		if ok {
+			replaced[previous] = menu
+			menu.ShowOnKey = previous.ShowOnKey
+			if previous.isFinalized {
+				menu.Finalize(previous.align)
+				continue
+			}
+		}
+		if mm.IsFinalized {
+			menu.Finalize(align)
+		}
+	}
+	for _, menu := range old {
+		if _, ok := replaced[menu]; !ok {
+			replaced[menu] = nil
+		}
+	}
+	mm.swapMenus(replaced)
+	for _, menu := range old {
+		menu.Release()
+	}
+
+	w.remember(menus)
+	if w.OnReload != nil {
+		w.OnReload(menus)
+	}
+	return nil
+}
+
+// swapMenus points the visible list and navigation history at replacement menus.
+// Menus replaced by nil are dropped.  Visibility and keyboard navigation carry over without
+// calling OnShow or OnHide since, from the player's point of view, nothing has been shown or hidden.
+func (mm *MenuManager) swapMenus(replaced map[*Menu]*Menu) {
+	visible := mm.visible
+	mm.visible = nil
+	for _, menu := range visible {
+		if replacement, ok := replaced[menu]; ok {
+			if replacement == nil {
+				continue
+			}
+			replacement.IsVisible = true
+			replacement.NavigationVia = menu.NavigationVia
+			replacement.NavigationIndex = -1
+			if menu.NavigationVia == NavigationKey && menu.NavigationIndex >= 0 && menu.NavigationIndex < len(replacement.Formatable) {
+				replacement.NavigationIndex = menu.NavigationIndex
+				if !replacement.Formatable[menu.NavigationIndex].IsNoop() {
+					replacement.setFocus(replacement.Formatable[menu.NavigationIndex])
+				}
+			}
+			menu = replacement
+		}
+		mm.setVisible(menu, true)
+	}
+
+	history := mm.history
+	mm.history = nil
+	for _, menu := range history {
+		if replacement, ok := replaced[menu]; ok {
+			menu = replacement
+		}
+		if menu != nil {
+			mm.history = append(mm.history, menu)
+		}
+	}
+	if _, ok := replaced[mm.pressed]; ok {
+		mm.pressed = nil
+	}
+}
+
+func (w *Watcher) remember(menus []*Menu) {
+	w.names = w.names[:0]
+	for _, menu := range menus {
+		w.names = append(w.names, menu.Name)
+	}
+}
+
+func (w *Watcher) fail(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+		return
+	}
+	MenuDebug("Unable to reload menus: " + err.Error())
+}
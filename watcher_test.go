@@ -0,0 +1,65 @@
+package glmenu
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMenuManagerSwapMenus(t *testing.T) {
+	mm := newTestManager("main", "option", "video")
+	mm.Show("main")
+	mm.Push("option")
+	mm.Menus["video"].Show()
+	option := mm.Menus["option"]
+	option.NavigationVia = NavigationKey
+	option.NavigationIndex = 1
+
+	hidden := false
+	replacement := &Menu{Name: "option", MenuManager: mm, IsModal: true, Formatable: []Formatable{&Header{}, &Header{}}}
+	replacement.OnHide = func(via Transition) { hidden = true }
+	mm.swapMenus(map[*Menu]*Menu{option: replacement, mm.Menus["video"]: nil})
+
+	if len(mm.Visible()) != 1 || mm.Visible()[0] != replacement || !replacement.IsVisible {
+		t.Error("the replacement should take the place of the visible menu", mm.Visible())
+	}
+	if mm.Current() != replacement || len(mm.history) != 2 {
+		t.Error("the history should point at the replacement")
+	}
+	if replacement.NavigationVia != NavigationKey || replacement.NavigationIndex != 1 {
+		t.Error("keyboard navigation should carry over", replacement.NavigationIndex)
+	}
+	if hidden {
+		t.Error("swapping should not count as hiding")
+	}
+}
+
+func TestWatcherErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "glmenu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mm := newTestManager("main")
+	main := mm.Menus["main"]
+	failures := 0
+	w := &Watcher{MenuManager: mm, Path: filepath.Join(dir, "menus.json"), names: []string{"main"}, OnError: func(error) { failures++ }}
+	w.Poll()
+	w.Poll()
+	if failures != 1 {
+		t.Error("a missing file should be reported once", failures)
+	}
+
+	if err := ioutil.WriteFile(w.Path, []byte(`{"menus": [{"name": "main",}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = w.Reload()
+	if _, ok := err.(*LoadError); !ok {
+		t.Error("expected a load error", err)
+	}
+	if len(mm.Menus) != 1 || mm.Menus["main"] != main {
+		t.Error("a failed reload should keep the current menus", mm.Menus)
+	}
+}
@@ -0,0 +1,197 @@
+package glmenu
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Checkbox toggles a boolean when clicked or followed with enter
+type Checkbox struct {
+	ID       string // key of the saved value, see ValueFile
+	Label    *Label
+	Text     string
+	Value    bool
+	OnChange func(value bool) // called when the user changes the value
+}
+
+// Slider picks a number between Min and Max.  Clicking the left or right end of the label, or pressing
+// the left and right keys while it is selected, moves the value by Step.  Clicking the middle steps up.
+type Slider struct {
+	ID       string
+	Label    *Label
+	Text     string
+	Value    float64
+	Min      float64
+	Max      float64
+	Step     float64
+	Bar      int    // number of cells in the bar drawn next to the value.  Zero draws no bar.
+	Format   string // formats the value, ie "%.0f%%".  Defaults to %v.
+	OnChange func(value float64)
+}
+
+// Selector picks one of Options, wrapping around at either end.  It is controlled like a Slider.
+type Selector struct {
+	ID       string
+	Label    *Label
+	Text     string
+	Options  []string
+	Index    int
+	OnChange func(index int)
+}
+
+// newWidgetLabel creates a CUSTOM label whose clicks and left/right keys call adjust with -1 or +1.
+// Clicks on the middle third of the label count as +1.
+func (menu *Menu) newWidgetLabel(config LabelConfig, adjust func(step int)) *Label {
+	config.Action = CUSTOM
+	label := menu.NewLabel("", config)
+	label.adjust = adjust
+	label.onRelease = func(xPos, yPos float64, button MouseClick, inBox bool) {
+		if !inBox {
+			return
+		}
+		X1, X2 := label.OrthoToScreenCoord()
+		step := 1
+		if X2.X > X1.X && (float32(xPos)-X1.X)/(X2.X-X1.X) < float32(1)/3 {
+			step = -1
+		}
+		adjust(step)
+	}
+	return label
+}
+
+func (menu *Menu) NewCheckbox(str string, value bool, config LabelConfig) *Checkbox {
+	checkbox := &Checkbox{Text: str, Value: value}
+	checkbox.Label = menu.newWidgetLabel(config, func(step int) {
+		checkbox.Set(!checkbox.Value)
+		checkbox.Label.binding.push(checkbox.Value)
+		if checkbox.OnChange != nil {
+			checkbox.OnChange(checkbox.Value)
+		}
+	})
+	checkbox.Label.widget = checkbox
+	checkbox.Set(value)
+	return checkbox
+}
+
+// Set changes the value without calling OnChange
+func (checkbox *Checkbox) Set(value bool) {
+	checkbox.Value = value
+	mark := "[ ]"
+	if value {
+		mark = "[x]"
+	}
+	checkbox.Label.SetString(mark + " " + checkbox.Text)
+	checkbox.Label.Menu.relayout()
+}
+
+func (menu *Menu) NewSlider(str string, value, min, max, step float64, config LabelConfig) *Slider {
+	slider := &Slider{Text: str, Min: min, Max: max, Step: step}
+	slider.Label = menu.newWidgetLabel(config, func(direction int) {
+		previous := slider.Value
+		slider.Set(slider.Value + float64(direction)*slider.Step)
+		if slider.Value != previous {
+			slider.Label.binding.push(slider.Value)
+		}
+		if slider.Value != previous && slider.OnChange != nil {
+			slider.OnChange(slider.Value)
+		}
+	})
+	slider.Label.widget = slider
+	slider.Set(value)
+	return slider
+}
+
+// Set snaps the value to a step, clamps it to Min and Max and updates the label without calling OnChange
+func (slider *Slider) Set(value float64) {
+	slider.Value = math.Max(slider.Min, math.Min(slider.Max, slider.snap(value)))
+	slider.Label.SetString(slider.String())
+	slider.Label.Menu.relayout()
+}
+
+// snap rounds value to the nearest Min + n*Step, dropping the float error left by adding up steps.
+// Sliders without a lower bound, whose Min is -math.MaxFloat64, count their steps from zero.
+func (slider *Slider) snap(value float64) float64 {
+	if slider.Step <= 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return value
+	}
+	base := slider.Min
+	if base == -math.MaxFloat64 {
+		base = 0
+	}
+	snapped := base + math.Floor((value-base)/slider.Step+0.5)*slider.Step
+	digits := decimals(slider.Step)
+	if d := decimals(base); d > digits {
+		digits = d
+	}
+	scale := math.Pow(10, float64(digits))
+	if rounded := math.Floor(snapped*scale+0.5) / scale; !math.IsInf(rounded, 0) {
+		return rounded
+	}
+	return snapped
+}
+
+// decimals counts the digits after the decimal point needed to write x exactly
+func decimals(x float64) int {
+	str := strconv.FormatFloat(x, 'f', -1, 64)
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		return len(str) - i - 1
+	}
+	return 0
+}
+
+func (slider *Slider) String() string {
+	format := slider.Format
+	if format == "" {
+		format = "%v"
+	}
+	value := fmt.Sprintf(format, slider.Value)
+	if slider.Bar <= 0 {
+		return fmt.Sprintf("%s < %s >", slider.Text, value)
+	}
+	filled := 0
+	if slider.Max > slider.Min {
+		filled = int(math.Floor((slider.Value-slider.Min)/(slider.Max-slider.Min)*float64(slider.Bar) + 0.5))
+	}
+	return fmt.Sprintf("%s [%s%s] %s", slider.Text, strings.Repeat("#", filled), strings.Repeat("-", slider.Bar-filled), value)
+}
+
+func (menu *Menu) NewSelector(str string, options []string, index int, config LabelConfig) *Selector {
+	selector := &Selector{Text: str, Options: options}
+	selector.Label = menu.newWidgetLabel(config, func(step int) {
+		if len(selector.Options) == 0 {
+			return
+		}
+		selector.Set((selector.Index + step + len(selector.Options)) % len(selector.Options))
+		selector.push()
+		if selector.OnChange != nil {
+			selector.OnChange(selector.Index)
+		}
+	})
+	selector.Label.widget = selector
+	selector.Set(index)
+	return selector
+}
+
+// Set selects an option by index without calling OnChange
+func (selector *Selector) Set(index int) {
+	if index < 0 || index >= len(selector.Options) {
+		index = 0
+	}
+	selector.Index = index
+	selected := ""
+	if len(selector.Options) > 0 {
+		selected = selector.Options[index]
+	}
+	selector.Label.SetString(fmt.Sprintf("%s < %s >", selector.Text, selected))
+	selector.Label.Menu.relayout()
+}
+
+// Selected returns the text of the current option
+func (selector *Selector) Selected() string {
+	if selector.Index < len(selector.Options) {
+		return selector.Options[selector.Index]
+	}
+	return ""
+}